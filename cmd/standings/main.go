@@ -0,0 +1,48 @@
+// Command standings rebuilds materialized contest standings from submissions
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+
+	"github.com/voidcontests/backend/internal/config"
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/repository/postgres"
+	"github.com/voidcontests/backend/internal/repository/postgres/standing"
+)
+
+func main() {
+	contestID := flag.Int("contest", 0, "ID of the contest to rebuild, all contests if omitted")
+	flag.Parse()
+
+	c := config.MustLoad()
+	ctx := context.Background()
+
+	db, err := postgres.New(&c.Postgres)
+	if err != nil {
+		slog.Error("postgresql: could not connect establish connection", sl.Err(err))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	repo := standing.New(db)
+
+	ids := []int32{int32(*contestID)}
+	if *contestID == 0 {
+		ids, err = repo.ListContestIDs(ctx)
+		if err != nil {
+			slog.Error("standings: can't list contests", sl.Err(err))
+			os.Exit(1)
+		}
+	}
+
+	for _, id := range ids {
+		if err := repo.Rebuild(ctx, id); err != nil {
+			slog.Error("standings: can't rebuild", sl.Err(err), slog.Int("contest_id", int(id)))
+			os.Exit(1)
+		}
+		slog.Info("standings: rebuilt", slog.Int("contest_id", int(id)))
+	}
+}
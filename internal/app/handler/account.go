@@ -98,6 +98,7 @@ func (h *Handler) GetAccount(c echo.Context) error {
 	return c.JSON(http.StatusOK, response.Account{
 		ID:       user.ID,
 		Username: user.Username,
+		Rating:   user.Rating,
 		Role: response.Role{
 			Name:                 role.Name,
 			CreatedProblemsLimit: role.CreatedProblemsLimit,
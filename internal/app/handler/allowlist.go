@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+func (h *Handler) GetAllowlist(c echo.Context) error {
+	op := "handler.GetAllowlist"
+	ctx := c.Request().Context()
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	users, err := h.repo.Contest.GetAllowlist(ctx, contest.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get allowlist: %v", op, err)
+	}
+
+	items := make([]response.User, len(users))
+	for i, u := range users {
+		items[i] = response.User{
+			ID:       u.ID,
+			Username: u.Username,
+		}
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) AddToAllowlist(c echo.Context) error {
+	op := "handler.AddToAllowlist"
+	ctx := c.Request().Context()
+
+	var body request.AllowlistEntry
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.repo.User.GetByUsername(ctx, body.Username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get user: %v", op, err)
+	}
+
+	if err := h.repo.Contest.AddToAllowlist(ctx, contest.ID, user.ID); err != nil {
+		return fmt.Errorf("%s: can't add user to allowlist: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+func (h *Handler) RemoveFromAllowlist(c echo.Context) error {
+	op := "handler.RemoveFromAllowlist"
+	ctx := c.Request().Context()
+
+	userID, ok := ExtractParamInt(c, "uid")
+	if !ok {
+		return Error(http.StatusBadRequest, "user ID should be an integer")
+	}
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	removed, err := h.repo.Contest.RemoveFromAllowlist(ctx, contest.ID, int32(userID))
+	if err != nil {
+		return fmt.Errorf("%s: can't remove user from allowlist: %v", op, err)
+	}
+	if !removed {
+		return Error(http.StatusNotFound, "user is not in allowlist")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
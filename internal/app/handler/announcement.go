@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/events"
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/pkg/requestid"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+const maxAnnouncementLength = 1000
+
+func (h *Handler) CreateAnnouncement(c echo.Context) error {
+	op := "handler.CreateAnnouncement"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	var body request.Announcement
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	if utf8.RuneCountInString(body.Text) > maxAnnouncementLength {
+		return Error(http.StatusBadRequest, fmt.Sprintf("announcement couldn't be longer than %d characters", maxAnnouncementLength))
+	}
+
+	contest, err := h.contestWithPermission(c, permModerate)
+	if err != nil {
+		return err
+	}
+
+	a, err := h.repo.Announcement.Create(ctx, contest.ID, claims.UserID, body.Text)
+	if err != nil {
+		return fmt.Errorf("%s: can't create announcement: %v", op, err)
+	}
+
+	h.publish(c, contest.ID, 0, events.AnnouncementCreated, announcementResponse(a))
+
+	return c.JSON(http.StatusCreated, response.ID{
+		ID: a.ID,
+	})
+}
+
+func (h *Handler) GetAnnouncements(c echo.Context) error {
+	op := "handler.GetAnnouncements"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	accessible, err := h.canAccess(ctx, contest, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: can't check contest access: %v", op, err)
+	}
+	if !accessible {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+
+	announcements, err := h.repo.Announcement.ListByContest(ctx, contest.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get announcements: %v", op, err)
+	}
+
+	items := make([]response.Announcement, len(announcements))
+	for i, a := range announcements {
+		items[i] = announcementResponse(a)
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) UpdateAnnouncement(c echo.Context) error {
+	op := "handler.UpdateAnnouncement"
+	ctx := c.Request().Context()
+
+	announcementID, ok := ExtractParamInt(c, "aid")
+	if !ok {
+		return Error(http.StatusBadRequest, "announcement ID should be an integer")
+	}
+
+	var body request.Announcement
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	if utf8.RuneCountInString(body.Text) > maxAnnouncementLength {
+		return Error(http.StatusBadRequest, fmt.Sprintf("announcement couldn't be longer than %d characters", maxAnnouncementLength))
+	}
+
+	contest, err := h.contestWithPermission(c, permModerate)
+	if err != nil {
+		return err
+	}
+
+	a, err := h.repo.Announcement.GetByID(ctx, int32(announcementID))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && a.ContestID != contest.ID) {
+		return Error(http.StatusNotFound, "announcement not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get announcement: %v", op, err)
+	}
+
+	a, err = h.repo.Announcement.Update(ctx, a.ID, body.Text)
+	if err != nil {
+		return fmt.Errorf("%s: can't update announcement: %v", op, err)
+	}
+
+	h.publish(c, contest.ID, 0, events.AnnouncementUpdated, announcementResponse(a))
+
+	return c.JSON(http.StatusOK, announcementResponse(a))
+}
+
+func (h *Handler) DeleteAnnouncement(c echo.Context) error {
+	op := "handler.DeleteAnnouncement"
+	ctx := c.Request().Context()
+
+	announcementID, ok := ExtractParamInt(c, "aid")
+	if !ok {
+		return Error(http.StatusBadRequest, "announcement ID should be an integer")
+	}
+
+	contest, err := h.contestWithPermission(c, permModerate)
+	if err != nil {
+		return err
+	}
+
+	a, err := h.repo.Announcement.GetByID(ctx, int32(announcementID))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && a.ContestID != contest.ID) {
+		return Error(http.StatusNotFound, "announcement not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get announcement: %v", op, err)
+	}
+
+	if err := h.repo.Announcement.Delete(ctx, a.ID); err != nil {
+		return fmt.Errorf("%s: can't delete announcement: %v", op, err)
+	}
+
+	h.publish(c, contest.ID, 0, events.AnnouncementDeleted, response.ID{ID: a.ID})
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// publish sends event to connected participants. Failures are only logged, because
+// the change is already persisted and clients are able to fetch it anyway.
+func (h *Handler) publish(c echo.Context, contestID, userID int32, typ string, payload any) {
+	if err := h.broker.Publish(c.Request().Context(), contestID, userID, typ, payload); err != nil {
+		slog.Error("can't publish event", sl.Err(err), slog.String("type", typ), slog.String("request_id", requestid.Get(c)))
+	}
+}
+
+func announcementResponse(a models.Announcement) response.Announcement {
+	return response.Announcement{
+		ID:        a.ID,
+		Text:      a.Text,
+		UpdatedAt: a.UpdatedAt,
+		CreatedAt: a.CreatedAt,
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/repository/postgres/contest"
+	"github.com/voidcontests/backend/pkg/ical"
+)
+
+// calendarLimit is a maximum amount of contests of each kind in a calendar feed
+const calendarLimit = 100
+
+// GetContestsCalendar returns upcoming and running public contests as iCalendar feed
+func (h *Handler) GetContestsCalendar(c echo.Context) error {
+	op := "handler.GetContestsCalendar"
+	ctx := c.Request().Context()
+
+	contests, _, err := h.repo.Contest.ListAll(ctx, contest.Filter{
+		Status: contest.StatusActive,
+		Limit:  calendarLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: can't get contests: %v", op, err)
+	}
+
+	return writeCalendar(c, "Contests", contests)
+}
+
+// GetPersonalCalendar returns calendar feed of public contests and contests the user is registered for,
+// including private ones. NOTE: feed is authorized by token in the path, since calendar clients can't log in.
+func (h *Handler) GetPersonalCalendar(c echo.Context) error {
+	op := "handler.GetPersonalCalendar"
+	ctx := c.Request().Context()
+
+	user, err := h.repo.User.GetByCalendarToken(ctx, c.Param("token"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "calendar not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get user: %v", op, err)
+	}
+
+	public, _, err := h.repo.Contest.ListAll(ctx, contest.Filter{
+		Status: contest.StatusActive,
+		Limit:  calendarLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: can't get contests: %v", op, err)
+	}
+
+	registered, _, err := h.repo.Contest.ListAll(ctx, contest.Filter{
+		Status:        contest.StatusActive,
+		UserID:        user.ID,
+		Participating: true,
+		Limit:         calendarLimit,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: can't get participated contests: %v", op, err)
+	}
+
+	contests := registered
+	for _, pc := range public {
+		if !slices.ContainsFunc(registered, func(rc models.Contest) bool { return rc.ID == pc.ID }) {
+			contests = append(contests, pc)
+		}
+	}
+
+	return writeCalendar(c, "My contests", contests)
+}
+
+// GetCalendarToken returns token of the personal calendar feed, issuing it on the first request
+func (h *Handler) GetCalendarToken(c echo.Context) error {
+	op := "handler.GetCalendarToken"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	token, err := h.repo.User.GetCalendarToken(ctx, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get calendar token: %v", op, err)
+	}
+
+	if token == "" {
+		return h.issueCalendarToken(c)
+	}
+
+	return c.JSON(http.StatusOK, response.Token{
+		Token: token,
+	})
+}
+
+// ResetCalendarToken issues a new token of the personal calendar feed, the previous feed url stops working
+func (h *Handler) ResetCalendarToken(c echo.Context) error {
+	return h.issueCalendarToken(c)
+}
+
+func (h *Handler) issueCalendarToken(c echo.Context) error {
+	op := "handler.issueCalendarToken"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Errorf("%s: can't generate token: %v", op, err)
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := h.repo.User.SetCalendarToken(ctx, claims.UserID, token); err != nil {
+		return fmt.Errorf("%s: can't set calendar token: %v", op, err)
+	}
+
+	return c.JSON(http.StatusOK, response.Token{
+		Token: token,
+	})
+}
+
+func writeCalendar(c echo.Context, name string, contests []models.Contest) error {
+	events := make([]ical.Event, len(contests))
+	for i, contest := range contests {
+		events[i] = ical.Event{
+			// NOTE: UID depends only on contest ID, so rescheduled contests are updated in calendars
+			UID:         fmt.Sprintf("contest-%d@voidcontests", contest.ID),
+			Summary:     contest.Title,
+			Description: contest.Description,
+			Start:       contest.StartTime,
+			End:         contest.EndTime,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, name, events); err != nil {
+		return fmt.Errorf("handler.writeCalendar: can't write calendar: %v", err)
+	}
+
+	return c.Blob(http.StatusOK, ical.ContentType, buf.Bytes())
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/events"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+func (h *Handler) CreateClarification(c echo.Context) error {
+	op := "handler.CreateClarification"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	var body request.CreateClarification
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	if contest.EndTime.Before(time.Now()) {
+		return Error(http.StatusForbidden, "contest already ended")
+	}
+
+	_, err = h.repo.Entry.Get(ctx, contest.ID, claims.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusForbidden, "no entry for contest")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get entry: %v", op, err)
+	}
+
+	var problemID *int32
+	if body.Charcode != "" {
+		p, err := h.repo.Problem.Get(ctx, contest.ID, strings.ToUpper(body.Charcode))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Error(http.StatusNotFound, "problem not found")
+		}
+		if err != nil {
+			return fmt.Errorf("%s: can't get problem: %v", op, err)
+		}
+		problemID = &p.ID
+	}
+
+	clarificationID, err := h.repo.Clarification.Create(ctx, contest.ID, problemID, claims.UserID, body.Question)
+	if err != nil {
+		return fmt.Errorf("%s: can't create clarification: %v", op, err)
+	}
+
+	return c.JSON(http.StatusCreated, response.ID{
+		ID: clarificationID,
+	})
+}
+
+func (h *Handler) GetClarifications(c echo.Context) error {
+	op := "handler.GetClarifications"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	// NOTE: moderators see all clarifications with their authors
+	moderator, err := h.can(ctx, contest, claims.UserID, permModerate)
+	if err != nil {
+		return fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+
+	var clarifications []models.Clarification
+	if moderator {
+		clarifications, err = h.repo.Clarification.ListAll(ctx, contest.ID)
+	} else {
+		_, err = h.repo.Entry.Get(ctx, contest.ID, claims.UserID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Error(http.StatusForbidden, "no entry for contest")
+		}
+		if err != nil {
+			return fmt.Errorf("%s: can't get entry: %v", op, err)
+		}
+
+		clarifications, err = h.repo.Clarification.ListRelevant(ctx, contest.ID, claims.UserID)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get clarifications: %v", op, err)
+	}
+
+	if err := h.repo.Clarification.MarkRead(ctx, contest.ID, claims.UserID); err != nil {
+		return fmt.Errorf("%s: can't mark clarifications as read: %v", op, err)
+	}
+
+	items := make([]response.Clarification, len(clarifications))
+	for i, cl := range clarifications {
+		items[i] = response.Clarification{
+			ID:         cl.ID,
+			Charcode:   cl.Charcode,
+			Question:   cl.Question,
+			Answer:     cl.Answer,
+			IsPublic:   cl.IsPublic,
+			AnsweredAt: cl.AnsweredAt,
+			CreatedAt:  cl.CreatedAt,
+		}
+
+		// NOTE: authors of public clarifications are hidden from other participants
+		if moderator || cl.UserID == claims.UserID {
+			items[i].Author = &response.User{
+				ID:       cl.UserID,
+				Username: cl.Username,
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) AnswerClarification(c echo.Context) error {
+	op := "handler.AnswerClarification"
+	ctx := c.Request().Context()
+
+	clarificationID, ok := ExtractParamInt(c, "clid")
+	if !ok {
+		return Error(http.StatusBadRequest, "clarification ID should be an integer")
+	}
+
+	var body request.AnswerClarification
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	contest, err := h.contestWithPermission(c, permModerate)
+	if err != nil {
+		return err
+	}
+
+	cl, err := h.repo.Clarification.GetByID(ctx, int32(clarificationID))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && cl.ContestID != contest.ID) {
+		return Error(http.StatusNotFound, "clarification not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get clarification: %v", op, err)
+	}
+
+	if err := h.repo.Clarification.Answer(ctx, cl.ID, body.Answer, body.Broadcast); err != nil {
+		return fmt.Errorf("%s: can't answer clarification: %v", op, err)
+	}
+
+	// NOTE: private answers are delivered only to the author of the question
+	var recipientID int32
+	if !body.Broadcast {
+		recipientID = cl.UserID
+	}
+
+	h.publish(c, contest.ID, recipientID, events.ClarificationAnswered, response.ID{ID: cl.ID})
+
+	return c.NoContent(http.StatusOK)
+}
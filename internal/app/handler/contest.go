@@ -1,9 +1,16 @@
 package handler
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,9 +19,13 @@ import (
 	"github.com/voidcontests/backend/internal/app/handler/dto/request"
 	"github.com/voidcontests/backend/internal/app/handler/dto/response"
 	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/repository/postgres/contest"
+	"github.com/voidcontests/backend/internal/scoring"
 	"github.com/voidcontests/backend/pkg/validate"
 )
 
+const maxLanguageLength = 10
+
 func (h *Handler) CreateContest(c echo.Context) error {
 	op := "handler.CreateContest"
 	ctx := c.Request().Context()
@@ -26,40 +37,237 @@ func (h *Handler) CreateContest(c echo.Context) error {
 		return Error(http.StatusBadRequest, "invalid body: missing required fields")
 	}
 
-	userrole, err := h.repo.User.GetRole(ctx, claims.UserID)
-	if err != nil {
-		return fmt.Errorf("%s: can't get role: %v", op, err)
+	// NOTE: older clients send only problem IDs
+	if len(body.Problems) == 0 {
+		for _, id := range body.ProblemsIDs {
+			body.Problems = append(body.Problems, request.ContestProblem{ProblemID: id})
+		}
+	}
+	if len(body.Problems) == 0 {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
 	}
 
-	if userrole.Name == models.RoleBanned {
-		return Error(http.StatusForbidden, "you are banned from creating contests")
+	if err := h.checkContestCreation(ctx, claims.UserID, body.Title); err != nil {
+		return err
 	}
 
-	if userrole.Name == models.RoleLimited {
-		cscount, err := h.repo.User.GetCreatedContestsCount(ctx, claims.UserID)
+	if body.TemplateID != 0 {
+		t, err := h.repo.Template.GetByID(ctx, body.TemplateID)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && t.OwnerID != claims.UserID) {
+			return Error(http.StatusNotFound, "template not found")
+		}
 		if err != nil {
-			return fmt.Errorf("%s: can't get created contests count: %v", op, err)
+			return fmt.Errorf("%s: can't get template: %v", op, err)
 		}
 
-		if cscount >= int(userrole.CreatedContestsLimit) {
-			return Error(http.StatusForbidden, "contests limit exceeded")
+		if body.DurationMins == 0 {
+			body.DurationMins = t.DurationMins
+		}
+		if body.ScoringMode == "" {
+			body.ScoringMode = t.ScoringMode
 		}
+		if body.AllowLateJoin == nil {
+			body.AllowLateJoin = &t.AllowLateJoin
+		}
+		if body.Languages == nil {
+			body.Languages = t.Languages
+		}
+	}
+
+	if body.AllowLateJoin == nil {
+		body.AllowLateJoin = new(bool)
 	}
 
-	occupied, err := h.repo.Contest.IsTitleOccupied(ctx, strings.ToLower(body.Title))
+	languages, err := normalizeLanguages(body.Languages)
 	if err != nil {
-		return fmt.Errorf("%s: can't verify that title isn't occupied: %v", op, err)
+		return err
 	}
-	if occupied {
-		return Error(http.StatusConflict, "title alredy taken")
+	body.Languages = languages
+
+	if limit := h.config.Contests.MaxProblems; len(body.Problems) > limit {
+		return Error(http.StatusBadRequest, fmt.Sprintf("maximum amount of problems in the contest is %d", limit))
 	}
 
-	// TODO: move this limitation somwhere as MAX_PROBLEMS
-	if len(body.ProblemsIDs) > 6 {
-		return Error(http.StatusBadRequest, "maximum about of problems in the contest is 6")
+	problemIDs := make([]int32, len(body.Problems))
+	for i := range body.Problems {
+		problemIDs[i] = body.Problems[i].ProblemID
+	}
+	if err := h.checkProblemsAvailable(ctx, claims.UserID, problemIDs); err != nil {
+		return err
 	}
 
-	contestID, err := h.repo.Contest.CreateWithProblemIDs(ctx, claims.UserID, body.Title, body.Description, body.StartTime, body.EndTime, body.DurationMins, body.MaxEntries, body.AllowLateJoin, body.ProblemsIDs)
+	charcodes := make(map[string]bool, len(body.Problems))
+	for i := range body.Problems {
+		if body.Problems[i].Points < 0 {
+			return Error(http.StatusBadRequest, "problem points couldn't be negative")
+		}
+
+		// NOTE: points are optional in request, every problem is worth 1 point by default
+		if body.Problems[i].Points == 0 {
+			body.Problems[i].Points = 1
+		}
+
+		if body.Problems[i].Charcode == "" {
+			body.Problems[i].Charcode = defaultCharcode(i)
+		}
+		body.Problems[i].Charcode = strings.ToUpper(body.Problems[i].Charcode)
+
+		if !charcodePattern.MatchString(body.Problems[i].Charcode) {
+			return Error(http.StatusBadRequest, "problem charcode should consist of 1 or 2 latin letters or digits")
+		}
+		if charcodes[body.Problems[i].Charcode] {
+			return Error(http.StatusBadRequest, fmt.Sprintf("duplicated problem charcode %s", body.Problems[i].Charcode))
+		}
+		charcodes[body.Problems[i].Charcode] = true
+	}
+
+	if body.ScoringMode == "" {
+		body.ScoringMode = scoring.ModePoints
+	}
+
+	if _, err := scoring.New(body.ScoringMode); err != nil {
+		return Error(http.StatusBadRequest, "unknown scoring mode")
+	}
+
+	if body.FreezeMins < 0 || body.EndTime.Sub(body.StartTime) < time.Duration(body.FreezeMins)*time.Minute {
+		return Error(http.StatusBadRequest, "freeze period should fit into the contest")
+	}
+
+	if body.MaxTeamSize < 0 {
+		return Error(http.StatusBadRequest, "max team size couldn't be negative")
+	}
+
+	// NOTE: contests are individual by default
+	if body.MaxTeamSize == 0 {
+		body.MaxTeamSize = 1
+	}
+
+	if body.IsRated && body.MaxTeamSize > 1 {
+		return Error(http.StatusBadRequest, "team contests couldn't be rated")
+	}
+
+	if body.MaxRating < 0 {
+		return Error(http.StatusBadRequest, "max rating couldn't be negative")
+	}
+
+	switch body.LeaderboardVisibility {
+	case "":
+		body.LeaderboardVisibility = models.LeaderboardPublic
+	case models.LeaderboardPublic, models.LeaderboardParticipants, models.LeaderboardAfterEnd, models.LeaderboardHidden:
+	default:
+		return Error(http.StatusBadRequest, "leaderboard visibility should be one of: public, participants, after_end, hidden")
+	}
+
+	problems := make([]models.ContestProblem, len(body.Problems))
+	for i, p := range body.Problems {
+		problems[i] = models.ContestProblem{
+			ProblemID: p.ProblemID,
+			Charcode:  p.Charcode,
+			Points:    p.Points,
+		}
+	}
+
+	contestID, err := h.repo.Contest.CreateWithProblems(ctx, models.Contest{
+		CreatorID:             claims.UserID,
+		Title:                 body.Title,
+		Description:           body.Description,
+		StartTime:             body.StartTime,
+		EndTime:               body.EndTime,
+		DurationMins:          body.DurationMins,
+		MaxEntries:            body.MaxEntries,
+		AllowLateJoin:         *body.AllowLateJoin,
+		ScoringMode:           body.ScoringMode,
+		FreezeMins:            body.FreezeMins,
+		MaxTeamSize:           body.MaxTeamSize,
+		IsPrivate:             body.IsPrivate,
+		InviteCode:            body.InviteCode,
+		IsRated:               body.IsRated,
+		MaxRating:             body.MaxRating,
+		Languages:             body.Languages,
+		LeaderboardVisibility: body.LeaderboardVisibility,
+	}, problems)
+	if err != nil {
+		return fmt.Errorf("%s: can't create contest: %v", op, err)
+	}
+
+	return c.JSON(http.StatusCreated, response.ID{
+		ID: contestID,
+	})
+}
+
+// CloneContest creates a new contest with settings and problems of the existing one
+func (h *Handler) CloneContest(c echo.Context) error {
+	op := "handler.CloneContest"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	var body request.CloneContest
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	if err := h.checkContestCreation(ctx, claims.UserID, body.Title); err != nil {
+		return err
+	}
+
+	if body.EndTime.Sub(body.StartTime) < time.Duration(contest.FreezeMins)*time.Minute {
+		return Error(http.StatusBadRequest, "freeze period should fit into the contest")
+	}
+
+	problems, err := h.repo.Contest.GetProblemset(ctx, contest.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get problemset: %v", op, err)
+	}
+
+	clone := models.Contest{
+		CreatorID:             claims.UserID,
+		Title:                 body.Title,
+		Description:           contest.Description,
+		StartTime:             body.StartTime,
+		EndTime:               body.EndTime,
+		DurationMins:          contest.DurationMins,
+		MaxEntries:            contest.MaxEntries,
+		AllowLateJoin:         contest.AllowLateJoin,
+		ScoringMode:           contest.ScoringMode,
+		FreezeMins:            contest.FreezeMins,
+		MaxTeamSize:           contest.MaxTeamSize,
+		IsPrivate:             contest.IsPrivate,
+		IsRated:               contest.IsRated,
+		MaxRating:             contest.MaxRating,
+		Languages:             contest.Languages,
+		LeaderboardVisibility: contest.LeaderboardVisibility,
+	}
+	// NOTE: invite code is never copied, so invitations to the original contest don't grant access to the clone
+	if contest.InviteCode != "" {
+		raw := make([]byte, 12)
+		if _, err := rand.Read(raw); err != nil {
+			return fmt.Errorf("%s: can't generate invite code: %v", op, err)
+		}
+		clone.InviteCode = hex.EncodeToString(raw)
+	}
+
+	cloneProblems := make([]models.ContestProblem, len(problems))
+	problemIDs := make([]int32, len(problems))
+	for i, p := range problems {
+		cloneProblems[i] = models.ContestProblem{
+			ProblemID: p.ID,
+			Charcode:  p.Charcode,
+			Points:    p.Points,
+		}
+		problemIDs[i] = p.ID
+	}
+
+	if err := h.checkProblemsAvailable(ctx, claims.UserID, problemIDs); err != nil {
+		return err
+	}
+
+	contestID, err := h.repo.Contest.CreateWithProblems(ctx, clone, cloneProblems)
 	if err != nil {
 		return fmt.Errorf("%s: can't create contest: %v", op, err)
 	}
@@ -88,8 +296,11 @@ func (h *Handler) GetContestByID(c echo.Context) error {
 		return fmt.Errorf("%s: can't get contest: %v", op, err)
 	}
 
-	// TODO: allow check previuos contests
-	if contest.EndTime.Before(time.Now()) {
+	accessible, err := h.canAccess(ctx, contest, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: can't check contest access: %v", op, err)
+	}
+	if !accessible {
 		return Error(http.StatusNotFound, "contest not found")
 	}
 
@@ -108,13 +319,30 @@ func (h *Handler) GetContestByID(c echo.Context) error {
 			ID:       contest.CreatorID,
 			Username: contest.CreatorUsername,
 		},
-		Participants:  contest.Participants,
-		StartTime:     contest.StartTime,
-		EndTime:       contest.EndTime,
-		DurationMins:  contest.DurationMins,
-		MaxEntries:    contest.MaxEntries,
-		AllowLateJoin: contest.AllowLateJoin,
-		CreatedAt:     contest.CreatedAt,
+		Participants:          contest.Participants,
+		StartTime:             contest.StartTime,
+		EndTime:               contest.EndTime,
+		DurationMins:          contest.DurationMins,
+		MaxEntries:            contest.MaxEntries,
+		AllowLateJoin:         contest.AllowLateJoin,
+		ScoringMode:           contest.ScoringMode,
+		FreezeMins:            contest.FreezeMins,
+		IsFrozen:              isFrozen(contest, time.Now()),
+		MaxTeamSize:           contest.MaxTeamSize,
+		IsPrivate:             contest.IsPrivate,
+		IsRated:               contest.IsRated,
+		MaxRating:             contest.MaxRating,
+		Languages:             contest.Languages,
+		LeaderboardVisibility: contest.LeaderboardVisibility,
+		CreatedAt:             contest.CreatedAt,
+	}
+
+	manager, err := h.can(ctx, contest, claims.UserID, permManage)
+	if err != nil {
+		return fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+	if manager {
+		cdetailed.InviteCode = contest.InviteCode
 	}
 
 	for i := range n {
@@ -123,6 +351,7 @@ func (h *Handler) GetContestByID(c echo.Context) error {
 			Charcode:   problems[i].Charcode,
 			Title:      problems[i].Title,
 			Difficulty: problems[i].Difficulty,
+			Points:     problems[i].Points,
 			Writer: response.User{
 				ID:       problems[i].WriterID,
 				Username: problems[i].WriterUsername,
@@ -144,7 +373,22 @@ func (h *Handler) GetContestByID(c echo.Context) error {
 		return c.JSON(http.StatusOK, cdetailed)
 	}
 
-	cdetailed.IsParticipant = true
+	// NOTE: entries created after the contest end are used for upsolving only
+	cdetailed.IsParticipant = !entry.Upsolving
+
+	if entry.DisqualifiedAt != nil {
+		cdetailed.Disqualification = &response.Disqualification{
+			Reason:         entry.DisqualificationReason,
+			DisqualifiedAt: *entry.DisqualifiedAt,
+		}
+	}
+
+	unread, err := h.repo.Clarification.CountUnread(ctx, contest.ID, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: can't count unread clarifications: %v", op, err)
+	}
+
+	cdetailed.UnreadClarifications = unread
 
 	statuses, err := h.repo.Submission.GetProblemStatuses(ctx, entry.ID)
 	if err != nil {
@@ -219,6 +463,46 @@ func (h *Handler) GetContests(c echo.Context) error {
 	op := "handler.GetContests"
 	ctx := c.Request().Context()
 
+	claims, authenticated := ExtractClaims(c)
+
+	f := contest.Filter{
+		Status: c.QueryParam("status"),
+		Query:  strings.TrimSpace(c.QueryParam("q")),
+	}
+
+	switch f.Status {
+	case "", contest.StatusActive, contest.StatusUpcoming, contest.StatusRunning, contest.StatusPast, contest.StatusAll:
+	case "finished":
+		f.Status = contest.StatusPast
+	default:
+		return Error(http.StatusBadRequest, "unknown contest status")
+	}
+
+	switch c.QueryParam("filter") {
+	case "":
+	case "mine":
+		f.Mine = true
+	case "participating":
+		f.Participating = true
+	default:
+		return Error(http.StatusBadRequest, "unknown contests filter")
+	}
+
+	if f.Mine || f.Participating {
+		if !authenticated {
+			return Error(http.StatusUnauthorized, "authentication required")
+		}
+		f.UserID = claims.UserID
+	}
+
+	// NOTE: sort key could be prefixed with `-` for descending order
+	sort := c.QueryParam("sort")
+	f.Desc = strings.HasPrefix(sort, "-")
+	f.Sort = strings.TrimPrefix(sort, "-")
+	if f.Sort != "" && f.Sort != contest.SortStartTime && f.Sort != contest.SortParticipants {
+		return Error(http.StatusBadRequest, "unknown sort key")
+	}
+
 	limit, ok := ExtractQueryParamInt(c, "limit")
 	if !ok {
 		limit = 10
@@ -229,11 +513,33 @@ func (h *Handler) GetContests(c echo.Context) error {
 		offset = 0
 	}
 
-	contests, total, err := h.repo.Contest.ListAll(ctx, limit, offset)
+	if limit < 0 || offset < 0 {
+		return Error(http.StatusBadRequest, "limit and offset couldn't be negative")
+	}
+
+	if raw := c.QueryParam("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw)
+		if err != nil {
+			return Error(http.StatusBadRequest, "invalid cursor")
+		}
+		f.Cursor = &cursor
+		offset = 0
+	}
+
+	// NOTE: one extra contest is requested to know if there is the next page
+	f.Limit = limit + 1
+	f.Offset = offset
+
+	contests, total, err := h.repo.Contest.ListAll(ctx, f)
 	if err != nil {
 		return fmt.Errorf("%s: can't get contests: %v", op, err)
 	}
 
+	hasNext := len(contests) > limit
+	if hasNext {
+		contests = contests[:limit]
+	}
+
 	items := make([]response.ContestListItem, 0)
 	for _, contest := range contests {
 		item := response.ContestListItem{
@@ -253,50 +559,173 @@ func (h *Handler) GetContests(c echo.Context) error {
 		items = append(items, item)
 	}
 
+	meta := response.Meta{
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasNext: hasNext,
+		HasPrev: offset > 0 || f.Cursor != nil,
+	}
+
+	if hasNext && len(contests) > 0 {
+		last := contests[len(contests)-1]
+		meta.NextCursor, err = encodeCursor(contest.Cursor{
+			ID:           last.ID,
+			StartTime:    last.StartTime,
+			EndTime:      last.EndTime,
+			Participants: last.Participants,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: can't encode cursor: %v", op, err)
+		}
+	}
+
 	return c.JSON(http.StatusOK, response.Pagination[response.ContestListItem]{
-		Meta: response.Meta{
-			Total:   total,
-			Limit:   limit,
-			Offset:  offset,
-			HasNext: offset+limit < total,
-			HasPrev: offset > 0,
-		},
+		Meta:  meta,
 		Items: items,
 	})
 }
 
-func (h *Handler) GetLeaderboard(c echo.Context) error {
-	op := "handler.GetLeaderboard"
-	ctx := c.Request().Context()
+func encodeCursor(cursor contest.Cursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
 
-	contestID, ok := ExtractParamInt(c, "cid")
-	if !ok {
-		return Error(http.StatusBadRequest, "contest ID should be an integer")
+func decodeCursor(raw string) (contest.Cursor, error) {
+	var cursor contest.Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, err
 	}
 
-	limit, ok := ExtractQueryParamInt(c, "limit")
-	if !ok {
-		limit = 50
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// canAccess reports whether the user could see the contest. Private contests are
+// visible only to their creators, staff, allowlisted users and participants.
+func (h *Handler) canAccess(ctx context.Context, contest *models.Contest, userID int32) (bool, error) {
+	if !contest.IsPrivate || contest.CreatorID == userID {
+		return true, nil
 	}
 
-	offset, ok := ExtractQueryParamInt(c, "offset")
-	if !ok {
-		offset = 0
+	// NOTE: anonymous users never see private contests
+	if userID == 0 {
+		return false, nil
 	}
 
-	leaderboard, total, err := h.repo.Contest.GetLeaderboard(ctx, contestID, limit, offset)
+	role, err := h.repo.Contest.GetRole(ctx, contest.ID, userID)
 	if err != nil {
-		return fmt.Errorf("%s: can't get leaderboard: %v", op, err)
+		return false, err
+	}
+	if role != "" {
+		return true, nil
 	}
 
-	return c.JSON(http.StatusOK, response.Pagination[models.LeaderboardEntry]{
-		Meta: response.Meta{
-			Total:   total,
-			Limit:   limit,
-			Offset:  offset,
-			HasNext: offset+limit < total,
-			HasPrev: offset > 0,
-		},
-		Items: leaderboard,
-	})
+	allowed, err := h.repo.Contest.IsAllowed(ctx, contest.ID, userID)
+	if err != nil {
+		return false, err
+	}
+	if allowed {
+		return true, nil
+	}
+
+	_, err = h.repo.Entry.Get(ctx, contest.ID, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// checkContestCreation verifies that the user is allowed to create one more contest with the title
+func (h *Handler) checkContestCreation(ctx context.Context, userID int32, title string) error {
+	op := "handler.checkContestCreation"
+
+	userrole, err := h.repo.User.GetRole(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get role: %v", op, err)
+	}
+
+	if userrole.Name == models.RoleBanned {
+		return Error(http.StatusForbidden, "you are banned from creating contests")
+	}
+
+	if userrole.Name == models.RoleLimited {
+		cscount, err := h.repo.User.GetCreatedContestsCount(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("%s: can't get created contests count: %v", op, err)
+		}
+
+		if cscount >= int(userrole.CreatedContestsLimit) {
+			return Error(http.StatusForbidden, "contests limit exceeded")
+		}
+	}
+
+	occupied, err := h.repo.Contest.IsTitleOccupied(ctx, strings.ToLower(title))
+	if err != nil {
+		return fmt.Errorf("%s: can't verify that title isn't occupied: %v", op, err)
+	}
+	if occupied {
+		return Error(http.StatusConflict, "title alredy taken")
+	}
+
+	return nil
+}
+
+// checkProblemsAvailable verifies that the user could use problems in own contests: only problems
+// written by the user and public ones are allowed, so unpublished problems of others are never revealed
+func (h *Handler) checkProblemsAvailable(ctx context.Context, userID int32, problemIDs []int32) error {
+	op := "handler.checkProblemsAvailable"
+
+	for i := range problemIDs {
+		if slices.Contains(problemIDs[:i], problemIDs[i]) {
+			return Error(http.StatusBadRequest, fmt.Sprintf("duplicated problem %d", problemIDs[i]))
+		}
+	}
+
+	available, err := h.repo.Problem.CountAvailable(ctx, userID, problemIDs)
+	if err != nil {
+		return fmt.Errorf("%s: can't count available problems: %v", op, err)
+	}
+	if available != len(problemIDs) {
+		return Error(http.StatusForbidden, "only own or public problems could be used in the contest")
+	}
+
+	return nil
+}
+
+// normalizeLanguages validates contest languages allowlist, and returns it lowercased and deduplicated
+func normalizeLanguages(languages []string) ([]string, error) {
+	normalized := make([]string, 0, len(languages))
+	for _, l := range languages {
+		l = strings.ToLower(strings.TrimSpace(l))
+		if l == "" || len(l) > maxLanguageLength {
+			return nil, Error(http.StatusBadRequest, fmt.Sprintf("language should be from 1 to %d characters long", maxLanguageLength))
+		}
+		if !slices.Contains(normalized, l) {
+			normalized = append(normalized, l)
+		}
+	}
+	return normalized, nil
+}
+
+// charcodePattern matches problem labels, which fit into `contest_problems.charcode`
+var charcodePattern = regexp.MustCompile(`^[A-Z0-9]{1,2}$`)
+
+// defaultCharcode labels the i-th problem like spreadsheet columns: A, B, ..., Z, AA, AB, ...
+func defaultCharcode(i int) string {
+	const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	if i < len(letters) {
+		return string(letters[i])
+	}
+	i -= len(letters)
+	return string(letters[i/len(letters)%len(letters)]) + string(letters[i%len(letters)])
 }
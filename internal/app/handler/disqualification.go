@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/events"
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/pkg/requestid"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+// maxDisqualificationReasonLength limits reasons, which are also delivered with events
+const maxDisqualificationReasonLength = 1000
+
+// DisqualifyEntry hides the entry from the leaderboard and forbids its further submissions.
+// NOTE: ratings, which are already calculated, are not recalculated.
+func (h *Handler) DisqualifyEntry(c echo.Context) error {
+	op := "handler.DisqualifyEntry"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	var body request.Disqualify
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	if len(body.Reason) > maxDisqualificationReasonLength {
+		return Error(http.StatusBadRequest, fmt.Sprintf("reason couldn't be longer than %d characters", maxDisqualificationReasonLength))
+	}
+
+	contest, e, err := h.contestEntry(c)
+	if err != nil {
+		return err
+	}
+
+	disqualified, err := h.repo.Entry.Disqualify(ctx, e, claims.UserID, body.Reason)
+	if err != nil {
+		return fmt.Errorf("%s: can't disqualify entry: %v", op, err)
+	}
+	if !disqualified {
+		return Error(http.StatusConflict, "entry is already disqualified")
+	}
+
+	h.publishToEntry(c, contest.ID, e, events.EntryDisqualified, response.Disqualification{Reason: body.Reason})
+	h.publish(c, contest.ID, 0, events.LeaderboardUpdated, nil)
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) ReinstateEntry(c echo.Context) error {
+	op := "handler.ReinstateEntry"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contest, e, err := h.contestEntry(c)
+	if err != nil {
+		return err
+	}
+
+	reinstated, err := h.repo.Entry.Reinstate(ctx, e, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: can't reinstate entry: %v", op, err)
+	}
+	if !reinstated {
+		return Error(http.StatusConflict, "entry is not disqualified")
+	}
+
+	h.publishToEntry(c, contest.ID, e, events.EntryReinstated, nil)
+	h.publish(c, contest.ID, 0, events.LeaderboardUpdated, nil)
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) GetAuditLog(c echo.Context) error {
+	op := "handler.GetAuditLog"
+	ctx := c.Request().Context()
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	records, err := h.repo.Audit.ListByContest(ctx, contest.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get audit log: %v", op, err)
+	}
+
+	items := make([]response.AuditRecord, len(records))
+	for i, r := range records {
+		items[i] = response.AuditRecord{
+			ID: r.ID,
+			Actor: response.User{
+				ID:       r.ActorID,
+				Username: r.ActorUsername,
+			},
+			Action:    r.Action,
+			Details:   r.Details,
+			CreatedAt: r.CreatedAt,
+		}
+
+		if r.TargetID != nil {
+			items[i].Target = &response.User{
+				ID:       *r.TargetID,
+				Username: r.TargetUsername,
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+// publishToEntry publishes private event to every user sharing the entry. As publish, it only logs failures.
+func (h *Handler) publishToEntry(c echo.Context, contestID int32, e models.Entry, typ string, payload any) {
+	if e.TeamID == nil {
+		h.publish(c, contestID, e.UserID, typ, payload)
+		return
+	}
+
+	members, err := h.repo.Team.GetMembers(c.Request().Context(), *e.TeamID)
+	if err != nil {
+		slog.Error("can't get team members", sl.Err(err), slog.String("type", typ), slog.String("request_id", requestid.Get(c)))
+		return
+	}
+
+	for _, m := range members {
+		h.publish(c, contestID, m.ID, typ, payload)
+	}
+}
+
+// contestEntry returns contest managed by the current user and the entry of the user from the `uid` path parameter.
+// NOTE: entries are addressed by users, since disqualified entries are not listed in the leaderboard.
+func (h *Handler) contestEntry(c echo.Context) (*models.Contest, models.Entry, error) {
+	op := "handler.contestEntry"
+	ctx := c.Request().Context()
+
+	userID, ok := ExtractParamInt(c, "uid")
+	if !ok {
+		return nil, models.Entry{}, Error(http.StatusBadRequest, "user ID should be an integer")
+	}
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return nil, models.Entry{}, err
+	}
+
+	e, err := h.repo.Entry.Get(ctx, contest.ID, int32(userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.Entry{}, Error(http.StatusNotFound, "entry not found")
+	}
+	if err != nil {
+		return nil, models.Entry{}, fmt.Errorf("%s: can't get entry: %v", op, err)
+	}
+
+	return contest, e, nil
+}
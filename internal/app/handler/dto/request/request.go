@@ -14,14 +14,85 @@ type CreateSession struct {
 
 // TODO: think about draft contests
 type CreateContestRequest struct {
-	Title         string    `json:"title" required:"true"`
-	Description   string    `json:"description"`
-	ProblemsIDs   []int32   `json:"problems_ids" required:"true"`
-	StartTime     time.Time `json:"start_time" required:"true"`
-	EndTime       time.Time `json:"end_time" required:"true"`
-	DurationMins  int32     `json:"duration_mins" requried:"true"`
-	MaxEntries    int32     `json:"max_entries"`
-	AllowLateJoin bool      `json:"allow_late_join"`
+	Title         string           `json:"title" required:"true"`
+	Description   string           `json:"description"`
+	Problems      []ContestProblem `json:"problems"`
+	ProblemsIDs   []int32          `json:"problems_ids"` // deprecated, used only if problems are omitted
+	StartTime     time.Time        `json:"start_time" required:"true"`
+	EndTime       time.Time        `json:"end_time" required:"true"`
+	DurationMins  int32            `json:"duration_mins" requried:"true"`
+	MaxEntries    int32            `json:"max_entries"`
+	AllowLateJoin *bool            `json:"allow_late_join"`
+	ScoringMode   string           `json:"scoring_mode"`
+	FreezeMins    int32            `json:"freeze_mins"`
+	MaxTeamSize   int32            `json:"max_team_size"`
+	IsPrivate     bool             `json:"is_private"`
+	InviteCode    string           `json:"invite_code"`
+	IsRated       bool             `json:"is_rated"`
+	// NOTE: only users with rating below max rating could join the contest, 0 - not limited
+	MaxRating int32 `json:"max_rating"`
+	// NOTE: empty languages list allows any language
+	Languages []string `json:"languages"`
+	// NOTE: leaderboard is public by default
+	LeaderboardVisibility string `json:"leaderboard_visibility"`
+	// NOTE: if template ID is provided, omitted fields are taken from the template
+	TemplateID int32 `json:"template_id"`
+}
+
+type CloneContest struct {
+	Title     string    `json:"title" required:"true"`
+	StartTime time.Time `json:"start_time" required:"true"`
+	EndTime   time.Time `json:"end_time" required:"true"`
+}
+
+type CreateTemplate struct {
+	Name          string   `json:"name" required:"true"`
+	DurationMins  int32    `json:"duration_mins" required:"true"`
+	ScoringMode   string   `json:"scoring_mode"`
+	AllowLateJoin bool     `json:"allow_late_join"`
+	Languages     []string `json:"languages"`
+}
+
+type Unfreeze struct {
+	// NOTE: if charcode is provided, only results of this problem are revealed
+	Charcode string `json:"charcode"`
+}
+
+type ContestProblem struct {
+	ProblemID int32 `json:"problem_id" required:"true"`
+	Points    int32 `json:"points"`
+	// NOTE: charcode is optional, problems are labeled by their position by default (A, B, ...)
+	Charcode string `json:"charcode"`
+}
+
+type CreateEntry struct {
+	// NOTE: if team ID is provided, the whole team is registered
+	TeamID int32 `json:"team_id"`
+	// NOTE: invite code is required to join private contest, unless user is in its allowlist
+	InviteCode string `json:"invite_code"`
+	// NOTE: if contest is full, user is put into its waitlist instead of getting an error
+	Waitlist bool `json:"waitlist"`
+}
+
+type AllowlistEntry struct {
+	Username string `json:"username" required:"true"`
+}
+
+type ContestRole struct {
+	Username string `json:"username" required:"true"`
+	Role     string `json:"role" required:"true"`
+}
+
+type Disqualify struct {
+	Reason string `json:"reason" required:"true"`
+}
+
+type CreateTeam struct {
+	Name string `json:"name" required:"true"`
+}
+
+type CreateInvitation struct {
+	Username string `json:"username" required:"true"`
 }
 
 type CreateProblemRequest struct {
@@ -34,6 +105,28 @@ type CreateProblemRequest struct {
 	Answer      string `json:"answer"`
 }
 
+// UpdateProblem changes only provided fields of the contest problem
+type UpdateProblem struct {
+	Charcode    *string `json:"charcode"`
+	Title       *string `json:"title"`
+	Statement   *string `json:"statement"`
+	Difficulty  *string `json:"difficulty"`
+	TimeLimitMS *int32  `json:"time_limit_ms"`
+	Answer      *string `json:"answer"`
+	Points      *int32  `json:"points"`
+}
+
+type PublishProblem struct {
+	// NOTE: tags are normalized to lowercase, e.g. dp, graphs, math
+	Tags   []string `json:"tags"`
+	Rating int32    `json:"rating"`
+}
+
+// ProblemOrder lists charcodes of all contest problems in the new order
+type ProblemOrder struct {
+	Charcodes []string `json:"charcodes" required:"true"`
+}
+
 type TC struct {
 	Input     string `json:"input"`
 	Output    string `json:"output"`
@@ -46,3 +139,19 @@ type CreateSubmissionRequest struct {
 	Code        string `json:"code"`
 	Language    string `json:"language"`
 }
+
+type CreateClarification struct {
+	// NOTE: charcode is optional, questions without it are about the whole contest
+	Charcode string `json:"charcode"`
+	Question string `json:"question" required:"true"`
+}
+
+type AnswerClarification struct {
+	Answer string `json:"answer" required:"true"`
+	// NOTE: broadcasted answers are visible to all participants
+	Broadcast bool `json:"broadcast"`
+}
+
+type Announcement struct {
+	Text string `json:"text" required:"true"`
+}
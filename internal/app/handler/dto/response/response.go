@@ -15,12 +15,18 @@ type Meta struct {
 	Offset  int  `json:"offset"`
 	HasNext bool `json:"has_next"`
 	HasPrev bool `json:"has_prev"`
+	// NextCursor is returned by cursor paginated lists, when there is the next page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type ID struct {
 	ID int32 `json:"id"`
 }
 
+type Waitlist struct {
+	Position int `json:"position"`
+}
+
 type Token struct {
 	Token string `json:"token"`
 }
@@ -28,9 +34,27 @@ type Token struct {
 type Account struct {
 	ID       int32  `json:"id"`
 	Username string `json:"username"`
+	Rating   int32  `json:"rating"`
 	Role     Role   `json:"role"`
 }
 
+type Profile struct {
+	ID            int32          `json:"id"`
+	Username      string         `json:"username"`
+	Rating        int32          `json:"rating"`
+	RatingHistory []RatingChange `json:"rating_history"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type RatingChange struct {
+	ContestID    int32     `json:"contest_id"`
+	ContestTitle string    `json:"contest_title"`
+	Rank         int32     `json:"rank"`
+	OldRating    int32     `json:"old_rating"`
+	NewRating    int32     `json:"new_rating"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 type Role struct {
 	Name                 string `json:"name"`
 	CreatedProblemsLimit int32  `json:"created_problems_limit"`
@@ -38,19 +62,31 @@ type Role struct {
 }
 
 type ContestDetailed struct {
-	ID            int32             `json:"id"`
-	Creator       User              `json:"creator"`
-	Title         string            `json:"title"`
-	Description   string            `json:"description"`
-	StartTime     time.Time         `json:"start_time"`
-	EndTime       time.Time         `json:"end_time"`
-	DurationMins  int32             `json:"duration_mins"`
-	MaxEntries    int32             `json:"max_entries,omitempty"`
-	Participants  int32             `json:"participants"`
-	AllowLateJoin bool              `json:"allow_late_join"`
-	IsParticipant bool              `json:"is_participant,omitempty"`
-	Problems      []ProblemListItem `json:"problems"`
-	CreatedAt     time.Time         `json:"created_at"`
+	ID                    int32             `json:"id"`
+	Creator               User              `json:"creator"`
+	Title                 string            `json:"title"`
+	Description           string            `json:"description"`
+	StartTime             time.Time         `json:"start_time"`
+	EndTime               time.Time         `json:"end_time"`
+	DurationMins          int32             `json:"duration_mins"`
+	MaxEntries            int32             `json:"max_entries,omitempty"`
+	Participants          int32             `json:"participants"`
+	AllowLateJoin         bool              `json:"allow_late_join"`
+	ScoringMode           string            `json:"scoring_mode"`
+	FreezeMins            int32             `json:"freeze_mins,omitempty"`
+	IsFrozen              bool              `json:"is_frozen,omitempty"`
+	MaxTeamSize           int32             `json:"max_team_size"`
+	IsPrivate             bool              `json:"is_private,omitempty"`
+	InviteCode            string            `json:"invite_code,omitempty"`
+	IsRated               bool              `json:"is_rated"`
+	MaxRating             int32             `json:"max_rating,omitempty"`
+	Languages             []string          `json:"languages"`
+	LeaderboardVisibility string            `json:"leaderboard_visibility"`
+	UnreadClarifications  int               `json:"unread_clarifications,omitempty"`
+	IsParticipant         bool              `json:"is_participant,omitempty"`
+	Disqualification      *Disqualification `json:"disqualification,omitempty"`
+	Problems              []ProblemListItem `json:"problems"`
+	CreatedAt             time.Time         `json:"created_at"`
 }
 
 type ProblemListItem struct {
@@ -60,7 +96,11 @@ type ProblemListItem struct {
 	Writer     User      `json:"writer"`
 	Title      string    `json:"title"`
 	Difficulty string    `json:"difficulty"`
+	Points     int32     `json:"points,omitempty"`
 	Status     string    `json:"status,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Rating     int32     `json:"rating,omitempty"`
+	Solved     bool      `json:"solved,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 }
 
@@ -76,6 +116,73 @@ type ContestListItem struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type LeaderboardEntry struct {
+	Rank     int               `json:"rank"`
+	UserID   int32             `json:"user_id"`
+	Username string            `json:"username"`
+	Points   float64           `json:"points"`
+	Solved   int               `json:"solved"`
+	Penalty  int               `json:"penalty"`
+	Pending  int               `json:"pending,omitempty"`
+	Team     *Team             `json:"team,omitempty"`
+	Problems []LeaderboardCell `json:"problems"`
+}
+
+type LeaderboardCell struct {
+	Charcode string  `json:"charcode"`
+	Status   string  `json:"status"`
+	Attempts int32   `json:"attempts"`
+	Points   float64 `json:"points"`
+	Pending  int32   `json:"pending,omitempty"`
+	// AcceptedMins is an amount of minutes since the contest start until acceptance
+	AcceptedMins *int `json:"accepted_mins,omitempty"`
+	FirstToSolve bool `json:"first_to_solve,omitempty"`
+}
+
+type ContestRole struct {
+	User      User      `json:"user"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Rejudge struct {
+	Submissions int64 `json:"submissions"`
+}
+
+type Disqualification struct {
+	Reason         string    `json:"reason"`
+	DisqualifiedAt time.Time `json:"disqualified_at"`
+}
+
+type AuditRecord struct {
+	ID        int32     `json:"id"`
+	Actor     User      `json:"actor"`
+	Action    string    `json:"action"`
+	Target    *User     `json:"target,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Team struct {
+	ID      int32  `json:"id"`
+	Name    string `json:"name"`
+	Members []User `json:"members,omitempty"`
+}
+
+type TeamDetailed struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	Captain   User      `json:"captain"`
+	Members   []User    `json:"members"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Invitation struct {
+	ID        int32     `json:"id"`
+	Team      Team      `json:"team"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type User struct {
 	ID       int32  `json:"id"`
 	Username string `json:"username"`
@@ -90,9 +197,17 @@ type Submission struct {
 	Code          string         `json:"code,omitempty"`
 	Language      string         `json:"language,omitempty"`
 	TestingReport *TestingReport `json:"testing_report,omitempty"`
+	Upsolving     bool           `json:"upsolving,omitempty"`
+	Practice      bool           `json:"practice,omitempty"`
 	CreatedAt     time.Time      `json:"created_at"`
 }
 
+// Progress describes practice of the user in the problem library
+type Progress struct {
+	Solved int `json:"solved"`
+	Tried  int `json:"tried"`
+}
+
 type TestingReport struct {
 	Passed     int         `json:"passed"`
 	Total      int         `json:"total"`
@@ -108,21 +223,55 @@ type FailedTest struct {
 
 // TODO: Rename this structure into ContestProblem
 type ProblemDetailed struct {
-	ID          int32     `json:"id"`
-	Charcode    string    `json:"charcode"`
-	ContestID   int32     `json:"contest_id"`
-	Writer      User      `json:"writer"`
-	Kind        string    `json:"kind"`
-	Title       string    `json:"title"`
-	Statement   string    `json:"statement"`
-	Examples    []TC      `json:"examples,omitempty"`
-	Difficulty  string    `json:"difficulty"`
-	Status      string    `json:"status,omitempty"`
-	TimeLimitMS int32     `json:"time_limit_ms,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int32  `json:"id"`
+	Charcode    string `json:"charcode,omitempty"`
+	ContestID   int32  `json:"contest_id,omitempty"`
+	Writer      User   `json:"writer"`
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	Statement   string `json:"statement"`
+	Examples    []TC   `json:"examples,omitempty"`
+	Difficulty  string `json:"difficulty"`
+	Points      int32  `json:"points"`
+	Status      string `json:"status,omitempty"`
+	TimeLimitMS int32  `json:"time_limit_ms,omitempty"`
+	// NOTE: library fields are provided only for public problems
+	IsPublic  bool      `json:"is_public,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Rating    int32     `json:"rating,omitempty"`
+	Solved    bool      `json:"solved,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type TC struct {
 	Input  string `json:"input"`
 	Output string `json:"output"`
 }
+
+type Clarification struct {
+	ID         int32      `json:"id"`
+	Charcode   string     `json:"charcode,omitempty"`
+	Author     *User      `json:"author,omitempty"`
+	Question   string     `json:"question"`
+	Answer     string     `json:"answer,omitempty"`
+	IsPublic   bool       `json:"is_public"`
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type Announcement struct {
+	ID        int32     `json:"id"`
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Template struct {
+	ID            int32     `json:"id"`
+	Name          string    `json:"name"`
+	DurationMins  int32     `json:"duration_mins"`
+	ScoringMode   string    `json:"scoring_mode"`
+	AllowLateJoin bool      `json:"allow_late_join"`
+	Languages     []string  `json:"languages"`
+	CreatedAt     time.Time `json:"created_at"`
+}
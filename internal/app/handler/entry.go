@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"net/http"
@@ -8,6 +9,12 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/events"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/repository/postgres/entry"
+	"github.com/voidcontests/backend/pkg/validate"
 )
 
 func (h *Handler) CreateEntry(c echo.Context) error {
@@ -21,6 +28,11 @@ func (h *Handler) CreateEntry(c echo.Context) error {
 		return Error(http.StatusBadRequest, "contest ID should be an integer")
 	}
 
+	var body request.CreateEntry
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body")
+	}
+
 	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Error(http.StatusNotFound, "contest not found")
@@ -29,32 +41,214 @@ func (h *Handler) CreateEntry(c echo.Context) error {
 		return fmt.Errorf("%s: can't get contest: %v", op, err)
 	}
 
-	entries, err := h.repo.Contest.GetEntriesCount(ctx, int32(contestID))
+	// NOTE: contest staff could join at any time to test the contest, its entry
+	// works like the upsolving one and never appears on the leaderboard
+	tester, err := h.can(ctx, contest, claims.UserID, permTest)
 	if err != nil {
-		return fmt.Errorf("%s: can't get entries: %v", op, err)
+		return fmt.Errorf("%s: can't check permission: %v", op, err)
 	}
 
-	if contest.MaxEntries != 0 && entries >= contest.MaxEntries {
-		return Error(http.StatusConflict, "max slots limit reached")
+	// NOTE: verified invite code is kept in the waitlist, so access could be re-checked on admission
+	var inviteCode string
+	if contest.IsPrivate && !tester {
+		allowed, err := h.repo.Contest.IsAllowed(ctx, contest.ID, claims.UserID)
+		if err != nil {
+			return fmt.Errorf("%s: can't check allowlist: %v", op, err)
+		}
+
+		if !allowed {
+			if body.InviteCode == "" {
+				return Error(http.StatusNotFound, "contest not found")
+			}
+			if contest.InviteCode == "" || subtle.ConstantTimeCompare([]byte(body.InviteCode), []byte(contest.InviteCode)) != 1 {
+				return Error(http.StatusForbidden, "invalid invite code")
+			}
+			inviteCode = body.InviteCode
+		}
 	}
 
-	// NOTE: disallow join if: contest already finished or (already started and no late joins)
-	if contest.EndTime.Before(time.Now()) || (contest.StartTime.Before(time.Now()) && !contest.AllowLateJoin) {
+	// NOTE: after the contest end anyone could join it for upsolving, without taking a slot
+	upsolving := tester || contest.EndTime.Before(time.Now())
+
+	// NOTE: disallow join if contest already started and no late joins
+	if !upsolving && contest.StartTime.Before(time.Now()) && !contest.AllowLateJoin {
 		return Error(http.StatusForbidden, "application time is over")
 	}
 
-	_, err = h.repo.Entry.Get(ctx, int32(contestID), claims.UserID)
-	if errors.Is(err, pgx.ErrNoRows) {
-		_, err = h.repo.Entry.Create(ctx, int32(contestID), claims.UserID)
+	if body.TeamID != 0 {
+		return h.createTeamEntry(c, contest, body.TeamID, upsolving)
+	}
+
+	if !upsolving && contest.MaxRating != 0 {
+		user, err := h.repo.User.GetByID(ctx, claims.UserID)
 		if err != nil {
-			return fmt.Errorf("%s: can't create entry: %v", op, err)
+			return fmt.Errorf("%s: can't get user: %v", op, err)
+		}
+
+		if user.Rating >= contest.MaxRating {
+			return Error(http.StatusForbidden, fmt.Sprintf("contest is only for participants with rating below %d", contest.MaxRating))
+		}
+	}
+
+	_, err = h.repo.Entry.Create(ctx, contest.ID, claims.UserID, nil, []int32{claims.UserID}, upsolving)
+	if errors.Is(err, entry.ErrAlreadyExists) {
+		return Error(http.StatusConflict, "user already has entry for this contest")
+	}
+	if errors.Is(err, entry.ErrContestFull) {
+		if body.Waitlist {
+			return h.joinWaitlist(c, contest, inviteCode)
+		}
+		return Error(http.StatusConflict, "max slots limit reached")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't create entry: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+func (h *Handler) createTeamEntry(c echo.Context, contest *models.Contest, teamID int32, upsolving bool) error {
+	op := "handler.createTeamEntry"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	if contest.MaxTeamSize <= 1 {
+		return Error(http.StatusForbidden, "contest doesn't allow team participation")
+	}
+
+	team, err := h.repo.Team.GetByID(ctx, teamID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "team not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get team: %v", op, err)
+	}
+
+	if team.CaptainID != claims.UserID {
+		return Error(http.StatusForbidden, "only team captain can register the team")
+	}
+
+	members, err := h.repo.Team.GetMembers(ctx, team.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get team members: %v", op, err)
+	}
+
+	if len(members) > int(contest.MaxTeamSize) {
+		return Error(http.StatusForbidden, fmt.Sprintf("maximum team size for the contest is %d", contest.MaxTeamSize))
+	}
+
+	ids := make([]int32, len(members))
+	for i, m := range members {
+		ids[i] = m.ID
+
+		if !upsolving && contest.MaxRating != 0 && m.Rating >= contest.MaxRating {
+			return Error(http.StatusForbidden, fmt.Sprintf("contest is only for participants with rating below %d", contest.MaxRating))
 		}
+	}
 
-		return c.NoContent(http.StatusCreated)
+	_, err = h.repo.Entry.Create(ctx, contest.ID, claims.UserID, &team.ID, ids, upsolving)
+	if errors.Is(err, entry.ErrAlreadyExists) {
+		return Error(http.StatusConflict, "some of team members already have entry for this contest")
+	}
+	if errors.Is(err, entry.ErrContestFull) {
+		return Error(http.StatusConflict, "max slots limit reached")
 	}
 	if err != nil {
+		return fmt.Errorf("%s: can't create entry: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusCreated)
+}
+
+// joinWaitlist puts the user into the waitlist of the full contest.
+// Users are admitted automatically when someone withdraws from the contest.
+func (h *Handler) joinWaitlist(c echo.Context, contest *models.Contest, inviteCode string) error {
+	op := "handler.joinWaitlist"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	_, err := h.repo.Entry.Get(ctx, contest.ID, claims.UserID)
+	if err == nil {
+		return Error(http.StatusConflict, "user already has entry for this contest")
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
 		return fmt.Errorf("%s: can't get entry: %v", op, err)
 	}
 
-	return Error(http.StatusConflict, "user already has entry for this contest")
+	position, err := h.repo.Entry.JoinWaitlist(ctx, contest.ID, claims.UserID, inviteCode)
+	if err != nil {
+		return fmt.Errorf("%s: can't join waitlist: %v", op, err)
+	}
+
+	return c.JSON(http.StatusAccepted, response.Waitlist{
+		Position: position,
+	})
+}
+
+func (h *Handler) DeleteEntry(c echo.Context) error {
+	op := "handler.DeleteEntry"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	e, err := h.repo.Entry.Get(ctx, contest.ID, claims.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// NOTE: user without an entry could only leave the waitlist
+		removed, err := h.repo.Entry.LeaveWaitlist(ctx, contest.ID, claims.UserID)
+		if err != nil {
+			return fmt.Errorf("%s: can't leave waitlist: %v", op, err)
+		}
+		if !removed {
+			return Error(http.StatusNotFound, "no entry for contest")
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get entry: %v", op, err)
+	}
+
+	if e.UserID != claims.UserID {
+		return Error(http.StatusForbidden, "only team captain can withdraw the team")
+	}
+
+	// NOTE: withdrawal would erase the disqualification
+	if e.DisqualifiedAt != nil {
+		return Error(http.StatusForbidden, "disqualified entry couldn't be withdrawn")
+	}
+
+	now := time.Now()
+
+	// NOTE: freed slot is given to the waitlist only while the contest still accepts participants
+	admit := !e.Upsolving && contest.MaxEntries != 0 && contest.EndTime.After(now) &&
+		(contest.StartTime.After(now) || contest.AllowLateJoin)
+
+	// NOTE: participant could withdraw only until the first submission, testers could submit before the start too
+	admitted, err := h.repo.Entry.Delete(ctx, contest.ID, e.ID, admit)
+	if errors.Is(err, entry.ErrHasSubmissions) {
+		return Error(http.StatusForbidden, "can't withdraw after the first submission")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't delete entry: %v", op, err)
+	}
+
+	if admitted != 0 {
+		h.publish(c, contest.ID, admitted, events.EntryAdmitted, nil)
+	}
+
+	return c.NoContent(http.StatusNoContent)
 }
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/standings"
+	"github.com/voidcontests/backend/pkg/xlsx"
+)
+
+func (h *Handler) ExportResultsCSV(c echo.Context) error {
+	op := "handler.ExportResultsCSV"
+
+	contest, table, err := h.resultsTable(c)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for _, row := range table {
+		record := make([]string, len(row))
+		for i, v := range row {
+			if s, ok := v.(string); ok {
+				record[i] = csvCell(s)
+			} else {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return fmt.Errorf("%s: can't write csv: %v", op, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("%s: can't write csv: %v", op, err)
+	}
+
+	setAttachment(c, contest, "csv")
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+func (h *Handler) ExportResultsXLSX(c echo.Context) error {
+	op := "handler.ExportResultsXLSX"
+
+	contest, table, err := h.resultsTable(c)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := xlsx.Write(&buf, "Results", table); err != nil {
+		return fmt.Errorf("%s: can't write xlsx: %v", op, err)
+	}
+
+	setAttachment(c, contest, "xlsx")
+	return c.Blob(http.StatusOK, xlsx.ContentType, buf.Bytes())
+}
+
+// resultsTable builds final standings of the contest as a table with header.
+// Results are available only to contest creator and admins, and are never frozen.
+func (h *Handler) resultsTable(c echo.Context) (*models.Contest, [][]any, error) {
+	op := "handler.resultsTable"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return nil, nil, Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	allowed, err := h.can(ctx, contest, claims.UserID, permManage)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+	if !allowed {
+		role, err := h.repo.User.GetRole(ctx, claims.UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: can't get role: %v", op, err)
+		}
+		if role.Name != models.RoleAdmin {
+			return nil, nil, Error(http.StatusForbidden, "only contest creator can export results")
+		}
+	}
+
+	problems, err := h.repo.Contest.GetProblemset(ctx, contest.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: can't get problemset: %v", op, err)
+	}
+
+	rows, err := standings.Compute(ctx, h.repo, contest, false, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: can't compute standings: %v", op, err)
+	}
+
+	header := []any{"Rank", "Username", "Team"}
+	for _, p := range problems {
+		header = append(header, p.Charcode, p.Charcode+" attempts")
+	}
+	header = append(header, "Solved", "Penalty", "Total")
+
+	table := [][]any{header}
+	for _, row := range rows {
+		record := []any{row.Rank, row.Username, row.TeamName}
+		for _, cell := range row.Cells {
+			record = append(record, cell.Points, cell.Attempts)
+		}
+		record = append(record, row.Solved, row.Penalty, row.Points)
+		table = append(table, record)
+	}
+
+	return contest, table, nil
+}
+
+// csvCell escapes user provided text, e.g. usernames and team names, so spreadsheets
+// don't evaluate it as a formula when the exported file is opened
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func setAttachment(c echo.Context, contest *models.Contest, ext string) {
+	filename := "contest-" + strconv.Itoa(int(contest.ID)) + "-results." + ext
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+}
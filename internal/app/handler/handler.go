@@ -3,9 +3,11 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"sync"
 
 	"github.com/labstack/echo/v4"
 	"github.com/voidcontests/backend/internal/config"
+	"github.com/voidcontests/backend/internal/events"
 	"github.com/voidcontests/backend/internal/jwt"
 	"github.com/voidcontests/backend/internal/repository"
 )
@@ -13,12 +15,18 @@ import (
 type Handler struct {
 	config *config.Config
 	repo   *repository.Repository
+	broker *events.Broker
+
+	hubsMu sync.Mutex
+	hubs   map[hubKey]*leaderboardHub
 }
 
-func New(c *config.Config, r *repository.Repository) *Handler {
+func New(c *config.Config, r *repository.Repository, b *events.Broker) *Handler {
 	return &Handler{
 		config: c,
 		repo:   r,
+		broker: b,
+		hubs:   make(map[hubKey]*leaderboardHub),
 	}
 }
 
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/scoring"
+	"github.com/voidcontests/backend/internal/standings"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+func (h *Handler) GetLeaderboard(c echo.Context) error {
+	op := "handler.GetLeaderboard"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	limit, ok := ExtractQueryParamInt(c, "limit")
+	if !ok {
+		limit = 50
+	}
+
+	offset, ok := ExtractQueryParamInt(c, "offset")
+	if !ok {
+		offset = 0
+	}
+
+	if limit < 0 || offset < 0 {
+		return Error(http.StatusBadRequest, "limit and offset couldn't be negative")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	staff, err := h.leaderboardAccess(ctx, contest, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: contest staff always sees the real leaderboard, while everyone else
+	// sees results submitted after the freeze as pending, except their own ones
+	frozen := isFrozen(contest, time.Now()) && !staff
+
+	// NOTE: own results are resolved by entry, so all team members see results of their team
+	var viewerEntryID int32
+	if frozen && claims.UserID != 0 {
+		e, err := h.repo.Entry.Get(ctx, contest.ID, claims.UserID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: can't get entry: %v", op, err)
+		}
+		viewerEntryID = e.ID
+	}
+
+	rows, err := standings.Compute(ctx, h.repo, contest, frozen, viewerEntryID)
+	if err != nil {
+		return fmt.Errorf("%s: can't compute standings: %v", op, err)
+	}
+
+	total := len(rows)
+	page := rows[min(offset, total):min(offset+limit, total)]
+
+	items, err := h.leaderboardItems(ctx, page)
+	if err != nil {
+		return fmt.Errorf("%s: %v", op, err)
+	}
+
+	return c.JSON(http.StatusOK, response.Pagination[response.LeaderboardEntry]{
+		Meta: response.Meta{
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+			HasNext: offset+limit < total,
+			HasPrev: offset > 0,
+		},
+		Items: items,
+	})
+}
+
+func (h *Handler) UnfreezeLeaderboard(c echo.Context) error {
+	op := "handler.UnfreezeLeaderboard"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	var body request.Unfreeze
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	allowed, err := h.can(ctx, contest, claims.UserID, permManage)
+	if err != nil {
+		return fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+	if !allowed {
+		return Error(http.StatusForbidden, "only contest creator can unfreeze leaderboard")
+	}
+
+	if contest.EndTime.After(time.Now()) {
+		return Error(http.StatusForbidden, "contest is not finished yet")
+	}
+
+	if body.Charcode == "" {
+		if err := h.repo.Contest.Unfreeze(ctx, contest.ID); err != nil {
+			return fmt.Errorf("%s: can't unfreeze leaderboard: %v", op, err)
+		}
+		return c.NoContent(http.StatusOK)
+	}
+
+	found, err := h.repo.Contest.RevealProblem(ctx, contest.ID, strings.ToUpper(body.Charcode))
+	if err != nil {
+		return fmt.Errorf("%s: can't reveal problem: %v", op, err)
+	}
+	if !found {
+		return Error(http.StatusNotFound, "problem not found")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+const (
+	cellSolved  = "solved"
+	cellTried   = "tried"
+	cellUntried = "untried"
+)
+
+// leaderboardItems converts standings rows into response items, with members of participating teams
+func (h *Handler) leaderboardItems(ctx context.Context, rows []scoring.Row) ([]response.LeaderboardEntry, error) {
+	var teamIDs []int32
+	for _, row := range rows {
+		if row.TeamID != 0 {
+			teamIDs = append(teamIDs, row.TeamID)
+		}
+	}
+
+	members := make(map[int32][]models.User)
+	if len(teamIDs) > 0 {
+		var err error
+		members, err = h.repo.Team.ListMembers(ctx, teamIDs)
+		if err != nil {
+			return nil, fmt.Errorf("can't get team members: %v", err)
+		}
+	}
+
+	items := make([]response.LeaderboardEntry, len(rows))
+	for i, row := range rows {
+		items[i] = response.LeaderboardEntry{
+			Rank:     row.Rank,
+			UserID:   row.UserID,
+			Username: row.Username,
+			Points:   row.Points,
+			Solved:   row.Solved,
+			Penalty:  row.Penalty,
+			Pending:  row.Pending,
+			Problems: make([]response.LeaderboardCell, len(row.Cells)),
+		}
+
+		for j, cell := range row.Cells {
+			status := cellUntried
+			if cell.Accepted {
+				status = cellSolved
+			} else if cell.Attempts > 0 || cell.Pending > 0 {
+				status = cellTried
+			}
+
+			items[i].Problems[j] = response.LeaderboardCell{
+				Charcode:     cell.Charcode,
+				Status:       status,
+				Attempts:     cell.Attempts,
+				Points:       cell.Points,
+				Pending:      cell.Pending,
+				FirstToSolve: cell.FirstToSolve,
+			}
+			if cell.Accepted {
+				items[i].Problems[j].AcceptedMins = &cell.AcceptedMins
+			}
+		}
+
+		if row.TeamID != 0 {
+			team := &response.Team{
+				ID:   row.TeamID,
+				Name: row.TeamName,
+			}
+			for _, m := range members[row.TeamID] {
+				team.Members = append(team.Members, response.User{
+					ID:       m.ID,
+					Username: m.Username,
+				})
+			}
+			items[i].Team = team
+		}
+	}
+
+	return items, nil
+}
+
+// isFrozen reports whether the contest leaderboard is frozen at the given moment
+func isFrozen(contest *models.Contest, now time.Time) bool {
+	if contest.FreezeMins == 0 || contest.Unfrozen {
+		return false
+	}
+
+	freezeTime := contest.EndTime.Add(-time.Duration(contest.FreezeMins) * time.Minute)
+	return !now.Before(freezeTime)
+}
+
+// leaderboardAccess checks whether the user could see the leaderboard of the contest according to
+// its visibility, and reports whether the user is a contest staff. Staff always sees the leaderboard.
+func (h *Handler) leaderboardAccess(ctx context.Context, contest *models.Contest, userID int32) (bool, error) {
+	op := "handler.leaderboardAccess"
+
+	staff, err := h.can(ctx, contest, userID, permView)
+	if err != nil {
+		return false, fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+	if staff {
+		return true, nil
+	}
+
+	accessible, err := h.canAccess(ctx, contest, userID)
+	if err != nil {
+		return false, fmt.Errorf("%s: can't check contest access: %v", op, err)
+	}
+	if !accessible {
+		return false, Error(http.StatusNotFound, "contest not found")
+	}
+
+	switch contest.LeaderboardVisibility {
+	case models.LeaderboardParticipants:
+		if userID == 0 {
+			return false, Error(http.StatusForbidden, "leaderboard is visible only to participants")
+		}
+
+		// NOTE: upsolving entries, e.g. created after the end, don't make user a participant
+		e, err := h.repo.Entry.Get(ctx, contest.ID, userID)
+		if errors.Is(err, pgx.ErrNoRows) || (err == nil && e.Upsolving) {
+			return false, Error(http.StatusForbidden, "leaderboard is visible only to participants")
+		}
+		if err != nil {
+			return false, fmt.Errorf("%s: can't get entry: %v", op, err)
+		}
+	case models.LeaderboardAfterEnd:
+		if contest.EndTime.After(time.Now()) {
+			return false, Error(http.StatusForbidden, "leaderboard is hidden until the contest end")
+		}
+	case models.LeaderboardHidden:
+		return false, Error(http.StatusForbidden, "leaderboard is hidden")
+	}
+
+	return false, nil
+}
@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/repository/postgres/problem"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+const (
+	maxProblemTags   = 10
+	maxProblemRating = 4000
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+// GetLibrary lists public problems, filtered by `q` (full-text search), `tags` (comma-separated, all should match),
+// `kind` and `min_rating`/`max_rating`
+func (h *Handler) GetLibrary(c echo.Context) error {
+	op := "handler.GetLibrary"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	f := problem.LibraryFilter{
+		Query:  strings.TrimSpace(c.QueryParam("q")),
+		Kind:   c.QueryParam("kind"),
+		UserID: claims.UserID,
+	}
+
+	if f.Kind != "" && f.Kind != models.TextAnswerProblem && f.Kind != models.CodingProblem {
+		return Error(http.StatusBadRequest, "unknown problem kind")
+	}
+
+	if raw := c.QueryParam("tags"); raw != "" {
+		tags, ok := normalizeTags(strings.Split(raw, ","))
+		if !ok {
+			return Error(http.StatusBadRequest, "invalid tags")
+		}
+		f.Tags = tags
+	}
+
+	if minRating, ok := ExtractQueryParamInt(c, "min_rating"); ok {
+		f.MinRating = int32(minRating)
+	}
+	if maxRating, ok := ExtractQueryParamInt(c, "max_rating"); ok {
+		f.MaxRating = int32(maxRating)
+	}
+
+	limit, ok := ExtractQueryParamInt(c, "limit")
+	if !ok {
+		limit = 10
+	}
+
+	offset, ok := ExtractQueryParamInt(c, "offset")
+	if !ok {
+		offset = 0
+	}
+
+	if limit < 0 || offset < 0 {
+		return Error(http.StatusBadRequest, "limit and offset couldn't be negative")
+	}
+
+	f.Limit = limit
+	f.Offset = offset
+
+	problems, total, err := h.repo.Problem.ListLibrary(ctx, f)
+	if err != nil {
+		return fmt.Errorf("%s: can't get problems: %v", op, err)
+	}
+
+	items := make([]response.ProblemListItem, 0)
+	for _, p := range problems {
+		items = append(items, response.ProblemListItem{
+			ID: p.ID,
+			Writer: response.User{
+				ID:       p.WriterID,
+				Username: p.WriterUsername,
+			},
+			Title:      p.Title,
+			Difficulty: p.Difficulty,
+			Tags:       p.Tags,
+			Rating:     p.Rating,
+			Solved:     p.Solved,
+			CreatedAt:  p.CreatedAt,
+		})
+	}
+
+	return c.JSON(http.StatusOK, response.Pagination[response.ProblemListItem]{
+		Meta: response.Meta{
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+			HasNext: offset+limit < total,
+			HasPrev: offset > 0,
+		},
+		Items: items,
+	})
+}
+
+// GetLibraryProblem returns public problem, unpublished problems are visible only to their writers
+func (h *Handler) GetLibraryProblem(c echo.Context) error {
+	op := "handler.GetLibraryProblem"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	problemID, ok := ExtractParamInt(c, "pid")
+	if !ok {
+		return Error(http.StatusBadRequest, "problem ID should be an integer")
+	}
+
+	p, err := h.repo.Problem.GetByID(ctx, int32(problemID), claims.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "problem not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get problem: %v", op, err)
+	}
+
+	if !p.IsPublic && p.WriterID != claims.UserID {
+		return Error(http.StatusNotFound, "problem not found")
+	}
+
+	etc, err := h.repo.Problem.GetExampleCases(ctx, p.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get tc examples: %v", op, err)
+	}
+
+	examples := make([]response.TC, len(etc))
+	for i := range etc {
+		examples[i] = response.TC{
+			Input:  etc[i].Input,
+			Output: etc[i].Output,
+		}
+	}
+
+	return c.JSON(http.StatusOK, response.ProblemDetailed{
+		ID:          p.ID,
+		Kind:        p.Kind,
+		Title:       p.Title,
+		Statement:   p.Statement,
+		Examples:    examples,
+		Difficulty:  p.Difficulty,
+		TimeLimitMS: p.TimeLimitMS,
+		IsPublic:    p.IsPublic,
+		Tags:        p.Tags,
+		Rating:      p.Rating,
+		Solved:      p.Solved,
+		CreatedAt:   p.CreatedAt,
+		Writer: response.User{
+			ID:       p.WriterID,
+			Username: p.WriterUsername,
+		},
+	})
+}
+
+// PublishProblem adds the problem to the library or updates its tags and rating
+func (h *Handler) PublishProblem(c echo.Context) error {
+	op := "handler.PublishProblem"
+	ctx := c.Request().Context()
+
+	var body request.PublishProblem
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	tags, ok := normalizeTags(body.Tags)
+	if !ok {
+		return Error(http.StatusBadRequest, "tags should consist of lowercase latin letters, digits and dashes, up to 32 characters")
+	}
+	if len(tags) > maxProblemTags {
+		return Error(http.StatusBadRequest, fmt.Sprintf("problem couldn't have more than %d tags", maxProblemTags))
+	}
+
+	if body.Rating < 0 || body.Rating > maxProblemRating {
+		return Error(http.StatusBadRequest, fmt.Sprintf("rating should be between 0 and %d", maxProblemRating))
+	}
+
+	p, err := h.writtenProblem(c)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: publishing would reveal the problem to participants of contests, which are not finished yet
+	used, err := h.repo.Problem.IsInUnfinishedContest(ctx, p.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't check problem usage: %v", op, err)
+	}
+	if used {
+		return Error(http.StatusConflict, "problem is used in a contest which is not finished yet")
+	}
+
+	if err := h.repo.Problem.Publish(ctx, p.ID, tags, body.Rating); err != nil {
+		return fmt.Errorf("%s: can't publish problem: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) UnpublishProblem(c echo.Context) error {
+	op := "handler.UnpublishProblem"
+	ctx := c.Request().Context()
+
+	p, err := h.writtenProblem(c)
+	if err != nil {
+		return err
+	}
+
+	if !p.IsPublic {
+		return Error(http.StatusNotFound, "problem is not published")
+	}
+
+	if err := h.repo.Problem.Unpublish(ctx, p.ID); err != nil {
+		return fmt.Errorf("%s: can't unpublish problem: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// writtenProblem returns problem from the `pid` path parameter, if the current user is its writer
+func (h *Handler) writtenProblem(c echo.Context) (*models.Problem, error) {
+	op := "handler.writtenProblem"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	problemID, ok := ExtractParamInt(c, "pid")
+	if !ok {
+		return nil, Error(http.StatusBadRequest, "problem ID should be an integer")
+	}
+
+	p, err := h.repo.Problem.GetByID(ctx, int32(problemID), claims.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, Error(http.StatusNotFound, "problem not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: can't get problem: %v", op, err)
+	}
+
+	if p.WriterID != claims.UserID {
+		return nil, Error(http.StatusForbidden, "only writer of the problem can publish it")
+	}
+
+	return p, nil
+}
+
+// normalizeTags lowercases and deduplicates tags, reporting false if any of them is invalid
+func normalizeTags(raw []string) ([]string, bool) {
+	tags := make([]string, 0, len(raw))
+	for _, tag := range raw {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, false
+		}
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, true
+}
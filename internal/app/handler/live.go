@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/events"
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/standings"
+	"golang.org/x/net/websocket"
+)
+
+// recomputeDelay collects bursts of verdicts into a single standings recomputation
+const recomputeDelay = time.Second
+
+const (
+	leaderboardSnapshot = "snapshot"
+	leaderboardUpdate   = "update"
+)
+
+type leaderboardMessage struct {
+	Type string                      `json:"type"`
+	Rows []response.LeaderboardEntry `json:"rows"`
+	// Removed contains user IDs of rows, which are not in the leaderboard anymore
+	Removed []int32 `json:"removed,omitempty"`
+}
+
+// hubKey identifies a single leaderboard view. Contest staff sees the real
+// leaderboard, while everyone else shares the public one, frozen if needed.
+// Participants of contests with freeze have their own views, since they see
+// their own results after the freeze, the same way as in GetLeaderboard.
+type hubKey struct {
+	contestID int32
+	real      bool
+	// viewerEntryID is an entry of the viewer, which results are not frozen
+	viewerEntryID int32
+}
+
+// leaderboardHub recomputes standings once per batch of updates and
+// broadcasts changed rows to all subscribers of the same view
+type leaderboardHub struct {
+	key    hubKey
+	cancel context.CancelFunc
+	// ready is closed once the first snapshot is loaded or failed with err
+	ready chan struct{}
+	err   error
+
+	mu      sync.Mutex
+	clients map[chan leaderboardMessage]struct{}
+	rows    []response.LeaderboardEntry
+	loaded  bool
+}
+
+// SubscribeLeaderboard streams leaderboard snapshot and its incremental updates over WebSocket
+func (h *Handler) SubscribeLeaderboard(c echo.Context) error {
+	op := "handler.SubscribeLeaderboard"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	staff, err := h.leaderboardAccess(ctx, contest, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	key := hubKey{
+		contestID: contest.ID,
+		real:      staff,
+	}
+
+	// NOTE: per-viewer hubs are used only while results could be hidden by freeze
+	if !staff && claims.UserID != 0 && contest.FreezeMins > 0 && !contest.Unfrozen && contest.EndTime.After(time.Now()) {
+		e, err := h.repo.Entry.Get(ctx, contest.ID, claims.UserID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: can't get entry: %v", op, err)
+		}
+		// NOTE: members of the same team share the view
+		key.viewerEntryID = e.ID
+	}
+
+	hub, ch, err := h.joinHub(key)
+	if err != nil {
+		return fmt.Errorf("%s: can't join leaderboard hub: %v", op, err)
+	}
+	defer h.leaveHub(hub, ch)
+
+	server := websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			// NOTE: hijacked connection keeps deadlines of the http server
+			ws.SetDeadline(time.Time{})
+
+			// NOTE: incoming messages are ignored, reading is only needed to notice disconnection
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard []byte
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case msg, ok := <-ch:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, msg); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// joinHub subscribes to the leaderboard view, starting its hub if needed.
+// Subscriber receives current snapshot as the first message.
+// NOTE: the first snapshot is loaded outside of hubsMu, so slow contests don't block other hubs.
+func (h *Handler) joinHub(key hubKey) (*leaderboardHub, chan leaderboardMessage, error) {
+	ch := make(chan leaderboardMessage, 16)
+
+	var ctx context.Context
+
+	h.hubsMu.Lock()
+	hub, ok := h.hubs[key]
+	if !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(context.Background())
+		hub = &leaderboardHub{
+			key:     key,
+			cancel:  cancel,
+			ready:   make(chan struct{}),
+			clients: make(map[chan leaderboardMessage]struct{}),
+		}
+		h.hubs[key] = hub
+	}
+	hub.mu.Lock()
+	hub.clients[ch] = struct{}{}
+	if hub.loaded {
+		ch <- leaderboardMessage{Type: leaderboardSnapshot, Rows: hub.rows}
+	}
+	hub.mu.Unlock()
+	h.hubsMu.Unlock()
+
+	if !ok {
+		h.startHub(ctx, hub)
+	}
+
+	<-hub.ready
+	if hub.err != nil {
+		h.leaveHub(hub, ch)
+		return nil, nil, hub.err
+	}
+
+	return hub, ch, nil
+}
+
+// startHub loads the first snapshot, sends it to already joined clients and starts recomputing standings
+func (h *Handler) startHub(ctx context.Context, hub *leaderboardHub) {
+	defer close(hub.ready)
+
+	updates, unsubscribe := h.broker.Subscribe(hub.key.contestID)
+
+	rows, err := h.liveStandings(ctx, hub.key)
+	if err != nil {
+		unsubscribe()
+		hub.err = err
+		return
+	}
+
+	hub.mu.Lock()
+	hub.rows = rows
+	hub.loaded = true
+	for ch := range hub.clients {
+		ch <- leaderboardMessage{Type: leaderboardSnapshot, Rows: rows}
+	}
+	hub.mu.Unlock()
+
+	go h.runHub(ctx, hub, updates, unsubscribe)
+}
+
+func (h *Handler) leaveHub(hub *leaderboardHub, ch chan leaderboardMessage) {
+	h.hubsMu.Lock()
+	defer h.hubsMu.Unlock()
+
+	hub.mu.Lock()
+	delete(hub.clients, ch)
+	empty := len(hub.clients) == 0
+	hub.mu.Unlock()
+
+	if empty {
+		hub.cancel()
+		// NOTE: failed hub could be already replaced by a new one
+		if h.hubs[hub.key] == hub {
+			delete(h.hubs, hub.key)
+		}
+	}
+}
+
+func (h *Handler) runHub(ctx context.Context, hub *leaderboardHub, updates <-chan events.Event, unsubscribe func()) {
+	defer unsubscribe()
+
+	timer := time.NewTimer(recomputeDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-updates:
+			if event.Type != events.LeaderboardUpdated || pending {
+				continue
+			}
+			pending = true
+			timer.Reset(recomputeDelay)
+		case <-timer.C:
+			pending = false
+
+			rows, err := h.liveStandings(ctx, hub.key)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Error("leaderboard hub: can't recompute standings", sl.Err(err), slog.Int("contest_id", int(hub.key.contestID)))
+				}
+				continue
+			}
+
+			hub.broadcast(rows)
+		}
+	}
+}
+
+func (h *Handler) liveStandings(ctx context.Context, key hubKey) ([]response.LeaderboardEntry, error) {
+	contest, err := h.repo.Contest.GetByID(ctx, key.contestID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get contest: %v", err)
+	}
+
+	frozen := !key.real && isFrozen(contest, time.Now())
+
+	rows, err := standings.Compute(ctx, h.repo, contest, frozen, key.viewerEntryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.leaderboardItems(ctx, rows)
+}
+
+// broadcast sends changed rows to all clients
+func (hub *leaderboardHub) broadcast(rows []response.LeaderboardEntry) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	previous := make(map[int32]response.LeaderboardEntry, len(hub.rows))
+	for _, row := range hub.rows {
+		previous[row.UserID] = row
+	}
+
+	msg := leaderboardMessage{Type: leaderboardUpdate, Rows: make([]response.LeaderboardEntry, 0)}
+	for _, row := range rows {
+		if prev, ok := previous[row.UserID]; !ok || !reflect.DeepEqual(prev, row) {
+			msg.Rows = append(msg.Rows, row)
+		}
+		delete(previous, row.UserID)
+	}
+	for userID := range previous {
+		msg.Removed = append(msg.Removed, userID)
+	}
+
+	hub.rows = rows
+
+	if len(msg.Rows) == 0 && len(msg.Removed) == 0 {
+		return
+	}
+
+	for ch := range hub.clients {
+		select {
+		case ch <- msg:
+		default:
+			// NOTE: client which can't keep up is disconnected, it will receive fresh snapshot on reconnect
+			close(ch)
+			delete(hub.clients, ch)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+type permission int
+
+const (
+	// permManage allows changing contest settings, its allowlist and staff
+	permManage permission = iota
+	// permView allows reading problems without entry and seeing the real leaderboard
+	permView
+	// permTest allows solving problems before the start, without appearing on the leaderboard
+	permTest
+	permEditProblems
+	// permModerate allows answering clarifications, making announcements and rejudging submissions
+	permModerate
+)
+
+// NOTE: contest creator has every permission, so it's not listed here
+var rolePermissions = map[string][]permission{
+	models.ContestRoleCoAuthor: {permView, permTest, permEditProblems},
+	models.ContestRoleTester:   {permView, permTest},
+	models.ContestRoleManager:  {permView, permModerate},
+}
+
+// can reports whether the user has the permission in the contest
+func (h *Handler) can(ctx context.Context, contest *models.Contest, userID int32, perm permission) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+
+	if contest.CreatorID == userID {
+		return true, nil
+	}
+
+	role, err := h.repo.Contest.GetRole(ctx, contest.ID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(rolePermissions[role], perm), nil
+}
+
+// contestWithPermission returns contest from the `cid` path parameter, if the current user has the permission in it
+func (h *Handler) contestWithPermission(c echo.Context, perm permission) (*models.Contest, error) {
+	op := "handler.contestWithPermission"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return nil, Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	allowed, err := h.can(ctx, contest, claims.UserID, perm)
+	if err != nil {
+		return nil, fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+	if !allowed {
+		return nil, Error(http.StatusForbidden, "not enough permissions in the contest")
+	}
+
+	return contest, nil
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/repository/postgres/submission"
+	"github.com/voidcontests/backend/pkg/requestid"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+// CreatePracticeSubmission submits solution of the library problem outside of any contest.
+// NOTE: practice submissions are judged as usual, but never affect any leaderboard.
+func (h *Handler) CreatePracticeSubmission(c echo.Context) error {
+	log := slog.With(slog.String("op", "handler.CreatePracticeSubmission"), slog.String("request_id", requestid.Get(c)))
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	problemID, ok := ExtractParamInt(c, "pid")
+	if !ok {
+		return Error(http.StatusBadRequest, "problem ID should be an integer")
+	}
+
+	var body request.CreateSubmissionRequest
+	if err := validate.Bind(c, &body); err != nil {
+		log.Debug("can't decode request body", sl.Err(err))
+		return Error(http.StatusBadRequest, "invalid body")
+	}
+
+	problem, err := h.practiceProblem(c, int32(problemID))
+	if err != nil {
+		return err
+	}
+
+	if body.ProblemKind != problem.Kind {
+		return Error(http.StatusBadRequest, "problem kind mismatch")
+	}
+
+	if problem.Kind == models.TextAnswerProblem {
+		verdict := submission.VerdictWrongAnswer
+		if problem.Answer == body.Answer {
+			verdict = submission.VerdictOK
+		}
+
+		s, err := h.repo.Submission.CreatePractice(ctx, claims.UserID, problem.ID, verdict, body.Answer, "", "")
+		if err != nil {
+			log.Error("can't create submission", sl.Err(err))
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, response.Submission{
+			ID:          s.ID,
+			ProblemID:   s.ProblemID,
+			ProblemKind: s.ProblemKind,
+			Verdict:     s.Verdict,
+			Answer:      body.Answer,
+			Practice:    true,
+			CreatedAt:   s.CreatedAt,
+		})
+	}
+
+	s, err := h.repo.Submission.CreatePractice(ctx, claims.UserID, problem.ID, submission.VerdictPending, "", body.Code, body.Language)
+	if err != nil {
+		log.Error("can't create submission", sl.Err(err))
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, response.Submission{
+		ID:          s.ID,
+		ProblemID:   s.ProblemID,
+		ProblemKind: s.ProblemKind,
+		Verdict:     submission.VerdictPending,
+		Practice:    true,
+		CreatedAt:   s.CreatedAt,
+	})
+}
+
+func (h *Handler) GetPracticeSubmissions(c echo.Context) error {
+	log := slog.With(slog.String("op", "handler.GetPracticeSubmissions"), slog.String("request_id", requestid.Get(c)))
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	problemID, ok := ExtractParamInt(c, "pid")
+	if !ok {
+		return Error(http.StatusBadRequest, "problem ID should be an integer")
+	}
+
+	limit, ok := ExtractQueryParamInt(c, "limit")
+	if !ok {
+		limit = 10
+	}
+
+	offset, ok := ExtractQueryParamInt(c, "offset")
+	if !ok {
+		offset = 0
+	}
+
+	submissions, total, err := h.repo.Submission.ListPractice(ctx, claims.UserID, int32(problemID), limit, offset)
+	if err != nil {
+		log.Error("can't get submissions", sl.Err(err))
+		return err
+	}
+
+	items := make([]response.Submission, len(submissions))
+	for i, submission := range submissions {
+		items[i] = response.Submission{
+			ID:          submission.ID,
+			ProblemID:   submission.ProblemID,
+			ProblemKind: submission.ProblemKind,
+			Verdict:     submission.Verdict,
+			Practice:    true,
+			CreatedAt:   submission.CreatedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, response.Pagination[response.Submission]{
+		Meta: response.Meta{
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+			HasNext: offset+limit < total,
+			HasPrev: offset > 0,
+		},
+		Items: items,
+	})
+}
+
+// GetPracticeProgress returns amounts of library problems solved and tried by the current user
+func (h *Handler) GetPracticeProgress(c echo.Context) error {
+	log := slog.With(slog.String("op", "handler.GetPracticeProgress"), slog.String("request_id", requestid.Get(c)))
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	solved, tried, err := h.repo.Submission.GetPracticeProgress(ctx, claims.UserID)
+	if err != nil {
+		log.Error("can't get practice progress", sl.Err(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.Progress{
+		Solved: solved,
+		Tried:  tried,
+	})
+}
+
+// practiceProblem returns problem available for practice: public one or written by the current user
+func (h *Handler) practiceProblem(c echo.Context, problemID int32) (*models.Problem, error) {
+	log := slog.With(slog.String("op", "handler.practiceProblem"), slog.String("request_id", requestid.Get(c)))
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	p, err := h.repo.Problem.GetByID(ctx, problemID, claims.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, Error(http.StatusNotFound, "problem not found")
+	}
+	if err != nil {
+		log.Error("can't get problem", sl.Err(err))
+		return nil, err
+	}
+
+	if !p.IsPublic && p.WriterID != claims.UserID {
+		return nil, Error(http.StatusNotFound, "problem not found")
+	}
+
+	return p, nil
+}
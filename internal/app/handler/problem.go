@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
@@ -138,14 +139,40 @@ func (h *Handler) GetContestProblem(c echo.Context) error {
 	}
 	charcode = strings.ToUpper(charcode)
 
-	entry, err := h.repo.Entry.Get(ctx, int32(contestID), claims.UserID)
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
 	if errors.Is(err, pgx.ErrNoRows) {
-		return Error(http.StatusForbidden, "no entry")
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
 	}
+
+	// NOTE: problems of finished contests are readable by anyone, while staff could read them at any time
+	staff, err := h.can(ctx, contest, claims.UserID, permView)
 	if err != nil {
+		return fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+	archived := staff || contest.EndTime.Before(time.Now())
+
+	entry, err := h.repo.Entry.Get(ctx, int32(contestID), claims.UserID)
+	hasEntry := err == nil
+	if errors.Is(err, pgx.ErrNoRows) && !archived {
+		return Error(http.StatusForbidden, "no entry")
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		return fmt.Errorf("%s: can't get entry: %v", op, err)
 	}
 
+	if !hasEntry {
+		accessible, err := h.canAccess(ctx, contest, claims.UserID)
+		if err != nil {
+			return fmt.Errorf("%s: can't check contest access: %v", op, err)
+		}
+		if !accessible {
+			return Error(http.StatusNotFound, "contest not found")
+		}
+	}
+
 	p, err := h.repo.Problem.Get(ctx, int32(contestID), charcode)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Error(http.StatusNotFound, "problem not found")
@@ -168,9 +195,12 @@ func (h *Handler) GetContestProblem(c echo.Context) error {
 		}
 	}
 
-	status, err := h.repo.Submission.GetProblemStatus(ctx, entry.ID, p.ID)
-	if err != nil {
-		return err
+	var status string
+	if hasEntry {
+		status, err = h.repo.Submission.GetProblemStatus(ctx, entry.ID, p.ID)
+		if err != nil {
+			return err
+		}
 	}
 
 	pdetailed := response.ProblemDetailed{
@@ -182,6 +212,7 @@ func (h *Handler) GetContestProblem(c echo.Context) error {
 		Statement:   p.Statement,
 		Examples:    examples,
 		Difficulty:  p.Difficulty,
+		Points:      p.Points,
 		Status:      status,
 		CreatedAt:   p.CreatedAt,
 		TimeLimitMS: p.TimeLimitMS,
@@ -193,3 +224,147 @@ func (h *Handler) GetContestProblem(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, pdetailed)
 }
+
+// UpdateContestProblem lets contest co-authors fix problems during the contest. Charcode and points
+// are changed only in the contest, while the problem content could be changed only by its writer.
+func (h *Handler) UpdateContestProblem(c echo.Context) error {
+	op := "handler.UpdateContestProblem"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	charcode := c.Param("charcode")
+	if len(charcode) > 2 {
+		return Error(http.StatusBadRequest, "problem charcode couldn't be longer than 2 characters")
+	}
+	charcode = strings.ToUpper(charcode)
+
+	var body request.UpdateProblem
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body")
+	}
+
+	contest, err := h.contestWithPermission(c, permEditProblems)
+	if err != nil {
+		return err
+	}
+
+	p, err := h.repo.Problem.Get(ctx, contest.ID, charcode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "problem not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get problem: %v", op, err)
+	}
+
+	if body.Charcode != nil && strings.ToUpper(*body.Charcode) != p.Charcode {
+		newCharcode := strings.ToUpper(*body.Charcode)
+		if !charcodePattern.MatchString(newCharcode) {
+			return Error(http.StatusBadRequest, "problem charcode should consist of 1 or 2 latin letters or digits")
+		}
+
+		// NOTE: participants refer problems by their charcodes, so labels are fixed after the start
+		if contest.StartTime.Before(time.Now()) {
+			return Error(http.StatusForbidden, "problem charcode couldn't be changed after the contest start")
+		}
+
+		_, err := h.repo.Problem.Get(ctx, contest.ID, newCharcode)
+		if err == nil {
+			return Error(http.StatusConflict, "problem charcode is already taken")
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: can't get problem: %v", op, err)
+		}
+
+		p.Charcode = newCharcode
+	}
+
+	// NOTE: problem content is shared with other contests and the library
+	content := body.Title != nil || body.Statement != nil || body.Difficulty != nil || body.TimeLimitMS != nil || body.Answer != nil
+	if content && p.WriterID != claims.UserID {
+		return Error(http.StatusForbidden, "only writer of the problem can change its content")
+	}
+
+	if body.Title != nil {
+		p.Title = *body.Title
+	}
+	if body.Statement != nil {
+		p.Statement = *body.Statement
+	}
+	if body.Difficulty != nil {
+		p.Difficulty = *body.Difficulty
+	}
+	if body.TimeLimitMS != nil {
+		p.TimeLimitMS = *body.TimeLimitMS
+	}
+	if body.Answer != nil {
+		p.Answer = *body.Answer
+	}
+	if body.Points != nil {
+		p.Points = *body.Points
+	}
+
+	if p.Title == "" {
+		return Error(http.StatusBadRequest, "problem title couldn't be empty")
+	}
+	if p.Points < 0 {
+		return Error(http.StatusBadRequest, "problem points couldn't be negative")
+	}
+	if p.TimeLimitMS < 0 {
+		return Error(http.StatusBadRequest, "time limit couldn't be negative")
+	}
+
+	// NOTE: already judged submissions keep their verdicts, until they are rejudged by moderators
+	if err := h.repo.Problem.Update(ctx, contest.ID, p, content); err != nil {
+		return fmt.Errorf("%s: can't update problem: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// ReorderContestProblems changes the order of problems in the contest, keeping their charcodes
+func (h *Handler) ReorderContestProblems(c echo.Context) error {
+	op := "handler.ReorderContestProblems"
+	ctx := c.Request().Context()
+
+	var body request.ProblemOrder
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	contest, err := h.contestWithPermission(c, permEditProblems)
+	if err != nil {
+		return err
+	}
+
+	problems, err := h.repo.Contest.GetProblemset(ctx, contest.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get problemset: %v", op, err)
+	}
+
+	// NOTE: the new order should be a permutation of all contest problems
+	remaining := make(map[string]bool, len(problems))
+	for _, p := range problems {
+		remaining[p.Charcode] = true
+	}
+
+	charcodes := make([]string, len(body.Charcodes))
+	for i, charcode := range body.Charcodes {
+		charcode = strings.ToUpper(charcode)
+		if !remaining[charcode] {
+			return Error(http.StatusBadRequest, fmt.Sprintf("unknown or duplicated problem charcode %s", charcode))
+		}
+		delete(remaining, charcode)
+		charcodes[i] = charcode
+	}
+
+	if len(remaining) > 0 {
+		return Error(http.StatusBadRequest, "all contest problems should be listed")
+	}
+
+	if err := h.repo.Contest.ReorderProblems(ctx, contest.ID, charcodes); err != nil {
+		return fmt.Errorf("%s: can't reorder problems: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+func (h *Handler) GetContestRoles(c echo.Context) error {
+	op := "handler.GetContestRoles"
+	ctx := c.Request().Context()
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	roles, err := h.repo.Contest.ListRoles(ctx, contest.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get roles: %v", op, err)
+	}
+
+	items := make([]response.ContestRole, len(roles))
+	for i, r := range roles {
+		items[i] = response.ContestRole{
+			User: response.User{
+				ID:       r.UserID,
+				Username: r.Username,
+			},
+			Role:      r.Role,
+			CreatedAt: r.CreatedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) SetContestRole(c echo.Context) error {
+	op := "handler.SetContestRole"
+	ctx := c.Request().Context()
+
+	var body request.ContestRole
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	if _, ok := rolePermissions[body.Role]; !ok {
+		return Error(http.StatusBadRequest, "role should be one of: co_author, tester, manager")
+	}
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.repo.User.GetByUsername(ctx, body.Username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get user: %v", op, err)
+	}
+
+	if user.ID == contest.CreatorID {
+		return Error(http.StatusBadRequest, "contest creator already has all permissions")
+	}
+
+	// NOTE: staff shouldn't compete in the contest it has access to
+	e, err := h.repo.Entry.Get(ctx, contest.ID, user.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s: can't get entry: %v", op, err)
+	}
+	if err == nil && !e.Upsolving {
+		return Error(http.StatusConflict, "user already participates in the contest")
+	}
+
+	if err := h.repo.Contest.SetRole(ctx, contest.ID, user.ID, body.Role); err != nil {
+		return fmt.Errorf("%s: can't set role: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) DeleteContestRole(c echo.Context) error {
+	op := "handler.DeleteContestRole"
+	ctx := c.Request().Context()
+
+	userID, ok := ExtractParamInt(c, "uid")
+	if !ok {
+		return Error(http.StatusBadRequest, "user ID should be an integer")
+	}
+
+	contest, err := h.contestWithPermission(c, permManage)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := h.repo.Contest.DeleteRole(ctx, contest.ID, int32(userID))
+	if err != nil {
+		return fmt.Errorf("%s: can't delete role: %v", op, err)
+	}
+	if !deleted {
+		return Error(http.StatusNotFound, "user has no role in the contest")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
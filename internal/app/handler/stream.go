@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/events"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+// GetContestEvents streams contest events to moderators and participants with Server-Sent Events.
+// Waitlisted users are subscribed too, so they are notified once admitted.
+func (h *Handler) GetContestEvents(c echo.Context) error {
+	op := "handler.GetContestEvents"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	contestID, ok := ExtractParamInt(c, "cid")
+	if !ok {
+		return Error(http.StatusBadRequest, "contest ID should be an integer")
+	}
+
+	contest, err := h.repo.Contest.GetByID(ctx, int32(contestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "contest not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get contest: %v", op, err)
+	}
+
+	// NOTE: moderators receive private events of all participants
+	moderator, err := h.can(ctx, contest, claims.UserID, permModerate)
+	if err != nil {
+		return fmt.Errorf("%s: can't check permission: %v", op, err)
+	}
+	if !moderator {
+		_, err = h.repo.Entry.Get(ctx, contest.ID, claims.UserID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%s: can't get entry: %v", op, err)
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			waiting, err := h.repo.Entry.IsWaiting(ctx, contest.ID, claims.UserID)
+			if err != nil {
+				return fmt.Errorf("%s: can't check waitlist: %v", op, err)
+			}
+			if !waiting {
+				return Error(http.StatusForbidden, "no entry for contest")
+			}
+		}
+	}
+
+	ch, unsubscribe := h.broker.Subscribe(contest.ID)
+	defer unsubscribe()
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+
+	// NOTE: stream lives much longer than the server write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		return fmt.Errorf("%s: can't reset write deadline: %v", op, err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		case event := <-ch:
+			// NOTE: private events are delivered only to their recipients and moderators
+			if event.UserID != 0 && event.UserID != claims.UserID && !moderator {
+				continue
+			}
+
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+func writeEvent(w *echo.Response, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+
+	w.Flush()
+	return nil
+}
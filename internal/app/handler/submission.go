@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -50,13 +51,14 @@ func (h *Handler) CreateSubmission(c echo.Context) error {
 		return err
 	}
 
-	if contest.StartTime.After(time.Now()) {
-		return Error(http.StatusForbidden, "contest is not started yet")
+	tester, err := h.can(ctx, contest, claims.UserID, permTest)
+	if err != nil {
+		log.Error("can't check permission", sl.Err(err))
+		return err
 	}
 
-	// TODO: maybe allow to submit solutions after end time if `contest.keep_as_training` is enabled
-	if contest.EndTime.Before(time.Now()) {
-		return Error(http.StatusForbidden, "contest alreay ended")
+	if contest.StartTime.After(time.Now()) && !tester {
+		return Error(http.StatusForbidden, "contest is not started yet")
 	}
 
 	entry, err := h.repo.Entry.Get(ctx, int32(contestID), claims.UserID)
@@ -69,6 +71,15 @@ func (h *Handler) CreateSubmission(c echo.Context) error {
 		return err
 	}
 
+	if entry.DisqualifiedAt != nil {
+		return Error(http.StatusForbidden, "entry is disqualified from the contest")
+	}
+
+	// NOTE: submissions after the contest end are accepted in upsolving mode,
+	// they are judged as usual, but never affect the leaderboard. Staff entries
+	// are always in upsolving mode.
+	upsolving := entry.Upsolving || contest.EndTime.Before(time.Now())
+
 	problem, err := h.repo.Problem.Get(ctx, int32(contestID), charcode)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Error(http.StatusNotFound, "problem not found")
@@ -86,7 +97,7 @@ func (h *Handler) CreateSubmission(c echo.Context) error {
 			verdict = submission.VerdictOK
 		}
 
-		s, err := h.repo.Submission.Create(ctx, entry.ID, problem.ID, verdict, body.Answer, "", "", 0, "")
+		s, err := h.repo.Submission.Create(ctx, entry.ID, problem.ID, verdict, body.Answer, "", "", 0, "", upsolving)
 		if err != nil {
 			log.Error("can't create submission", sl.Err(err))
 			return err
@@ -98,9 +109,14 @@ func (h *Handler) CreateSubmission(c echo.Context) error {
 			ProblemKind: s.ProblemKind,
 			Verdict:     string(s.Verdict),
 			Answer:      body.Answer,
+			Upsolving:   s.Upsolving,
 			CreatedAt:   s.CreatedAt,
 		})
 	} else if body.ProblemKind == models.CodingProblem {
+		if len(contest.Languages) > 0 && !slices.Contains(contest.Languages, strings.ToLower(body.Language)) {
+			return Error(http.StatusBadRequest, "language is not allowed in this contest")
+		}
+
 		tcs, err := h.repo.Problem.GetTestCases(ctx, problem.ID)
 		if err != nil {
 			log.Error("can't get test cases for problem", sl.Err(err))
@@ -113,7 +129,7 @@ func (h *Handler) CreateSubmission(c echo.Context) error {
 			rtcs[i].Output = tcs[i].Output
 		}
 
-		s, err := h.repo.Submission.Create(ctx, entry.ID, problem.ID, submission.VerdictPending, "", body.Code, body.Language, 0, "")
+		s, err := h.repo.Submission.Create(ctx, entry.ID, problem.ID, submission.VerdictPending, "", body.Code, body.Language, 0, "", upsolving)
 		if err != nil {
 			log.Error("can't create submission", sl.Err(err))
 			return err
@@ -124,6 +140,7 @@ func (h *Handler) CreateSubmission(c echo.Context) error {
 			ProblemID:   s.ProblemID,
 			ProblemKind: s.ProblemKind,
 			Verdict:     submission.VerdictPending,
+			Upsolving:   s.Upsolving,
 			CreatedAt:   s.CreatedAt,
 		})
 	}
@@ -158,6 +175,8 @@ func (h *Handler) GetSubmissionByID(c echo.Context) error {
 			ProblemKind: s.ProblemKind,
 			Verdict:     s.Verdict,
 			Answer:      s.Answer,
+			Upsolving:   s.Upsolving,
+			Practice:    s.EntryID == nil,
 			CreatedAt:   s.CreatedAt,
 		})
 	}
@@ -177,6 +196,8 @@ func (h *Handler) GetSubmissionByID(c echo.Context) error {
 			Verdict:     s.Verdict,
 			Code:        s.Code,
 			Language:    s.Language,
+			Upsolving:   s.Upsolving,
+			Practice:    s.EntryID == nil,
 			CreatedAt:   s.CreatedAt,
 		})
 	}
@@ -191,6 +212,8 @@ func (h *Handler) GetSubmissionByID(c echo.Context) error {
 			Verdict:     s.Verdict,
 			Code:        s.Code,
 			Language:    s.Language,
+			Upsolving:   s.Upsolving,
+			Practice:    s.EntryID == nil,
 			TestingReport: &response.TestingReport{
 				Passed: int(s.PassedTestsCount),
 				Total:  int(ttc),
@@ -211,6 +234,8 @@ func (h *Handler) GetSubmissionByID(c echo.Context) error {
 		Verdict:     s.Verdict,
 		Code:        s.Code,
 		Language:    s.Language,
+		Upsolving:   s.Upsolving,
+		Practice:    s.EntryID == nil,
 		TestingReport: &response.TestingReport{
 			Passed: int(s.PassedTestsCount),
 			Total:  int(ttc),
@@ -275,6 +300,7 @@ func (h *Handler) GetSubmissions(c echo.Context) error {
 			ProblemID:   submission.ProblemID,
 			ProblemKind: submission.ProblemKind,
 			Verdict:     submission.Verdict,
+			Upsolving:   submission.Upsolving,
 			CreatedAt:   submission.CreatedAt,
 		}
 	}
@@ -290,3 +316,64 @@ func (h *Handler) GetSubmissions(c echo.Context) error {
 		Items: items,
 	})
 }
+
+func (h *Handler) RejudgeSubmission(c echo.Context) error {
+	log := slog.With(slog.String("op", "handler.RejudgeSubmission"), slog.String("request_id", requestid.Get(c)))
+	ctx := c.Request().Context()
+
+	submissionID, ok := ExtractParamInt(c, "sid")
+	if !ok {
+		return Error(http.StatusBadRequest, "submission ID should be an integer")
+	}
+
+	contest, err := h.contestWithPermission(c, permModerate)
+	if err != nil {
+		return err
+	}
+
+	found, err := h.repo.Submission.RejudgeSubmission(ctx, contest.ID, int32(submissionID))
+	if err != nil {
+		log.Error("can't rejudge submission", sl.Err(err))
+		return err
+	}
+	if !found {
+		return Error(http.StatusNotFound, "submission not found or not judged yet")
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) RejudgeProblem(c echo.Context) error {
+	log := slog.With(slog.String("op", "handler.RejudgeProblem"), slog.String("request_id", requestid.Get(c)))
+	ctx := c.Request().Context()
+
+	charcode := c.Param("charcode")
+	if len(charcode) > 2 {
+		return Error(http.StatusBadRequest, "problem's `charcode` couldn't be longer than 2 characters")
+	}
+	charcode = strings.ToUpper(charcode)
+
+	contest, err := h.contestWithPermission(c, permModerate)
+	if err != nil {
+		return err
+	}
+
+	problem, err := h.repo.Problem.Get(ctx, contest.ID, charcode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "problem not found")
+	}
+	if err != nil {
+		log.Error("can't get problem", sl.Err(err))
+		return err
+	}
+
+	n, err := h.repo.Submission.RejudgeProblem(ctx, contest.ID, problem.ID)
+	if err != nil {
+		log.Error("can't rejudge problem", sl.Err(err))
+		return err
+	}
+
+	return c.JSON(http.StatusOK, response.Rejudge{
+		Submissions: n,
+	})
+}
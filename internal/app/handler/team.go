@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/repository/postgres/team"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+const maxTeamNameLength = 50
+
+func (h *Handler) CreateTeam(c echo.Context) error {
+	op := "handler.CreateTeam"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	var body request.CreateTeam
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		return Error(http.StatusBadRequest, "team name couldn't be empty")
+	}
+	if utf8.RuneCountInString(body.Name) > maxTeamNameLength {
+		return Error(http.StatusBadRequest, fmt.Sprintf("team name couldn't be longer than %d characters", maxTeamNameLength))
+	}
+
+	occupied, err := h.repo.Team.IsNameOccupied(ctx, body.Name)
+	if err != nil {
+		return fmt.Errorf("%s: can't verify that name isn't occupied: %v", op, err)
+	}
+	if occupied {
+		return Error(http.StatusConflict, "team name already taken")
+	}
+
+	teamID, err := h.repo.Team.Create(ctx, claims.UserID, body.Name)
+	if err != nil {
+		return fmt.Errorf("%s: can't create team: %v", op, err)
+	}
+
+	return c.JSON(http.StatusCreated, response.ID{
+		ID: teamID,
+	})
+}
+
+func (h *Handler) GetTeamByID(c echo.Context) error {
+	op := "handler.GetTeamByID"
+	ctx := c.Request().Context()
+
+	teamID, ok := ExtractParamInt(c, "tid")
+	if !ok {
+		return Error(http.StatusBadRequest, "team ID should be an integer")
+	}
+
+	team, err := h.repo.Team.GetByID(ctx, int32(teamID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "team not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get team: %v", op, err)
+	}
+
+	members, err := h.repo.Team.GetMembers(ctx, team.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get team members: %v", op, err)
+	}
+
+	tdetailed := response.TeamDetailed{
+		ID:        team.ID,
+		Name:      team.Name,
+		Members:   make([]response.User, len(members)),
+		CreatedAt: team.CreatedAt,
+	}
+
+	for i, m := range members {
+		tdetailed.Members[i] = response.User{
+			ID:       m.ID,
+			Username: m.Username,
+		}
+
+		if m.ID == team.CaptainID {
+			tdetailed.Captain = tdetailed.Members[i]
+		}
+	}
+
+	return c.JSON(http.StatusOK, tdetailed)
+}
+
+func (h *Handler) CreateInvitation(c echo.Context) error {
+	op := "handler.CreateInvitation"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	teamID, ok := ExtractParamInt(c, "tid")
+	if !ok {
+		return Error(http.StatusBadRequest, "team ID should be an integer")
+	}
+
+	var body request.CreateInvitation
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	team, err := h.repo.Team.GetByID(ctx, int32(teamID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "team not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get team: %v", op, err)
+	}
+
+	if team.CaptainID != claims.UserID {
+		return Error(http.StatusForbidden, "only team captain can invite members")
+	}
+
+	user, err := h.repo.User.GetByUsername(ctx, body.Username)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get user: %v", op, err)
+	}
+
+	member, err := h.repo.Team.IsMember(ctx, team.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't check team membership: %v", op, err)
+	}
+	if member {
+		return Error(http.StatusConflict, "user is already a member of the team")
+	}
+
+	invitationID, err := h.repo.Team.CreateInvitation(ctx, team.ID, user.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't create invitation: %v", op, err)
+	}
+
+	return c.JSON(http.StatusCreated, response.ID{
+		ID: invitationID,
+	})
+}
+
+func (h *Handler) GetInvitations(c echo.Context) error {
+	op := "handler.GetInvitations"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	invitations, err := h.repo.Team.ListInvitations(ctx, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get invitations: %v", op, err)
+	}
+
+	items := make([]response.Invitation, len(invitations))
+	for i, inv := range invitations {
+		items[i] = response.Invitation{
+			ID: inv.ID,
+			Team: response.Team{
+				ID:   inv.TeamID,
+				Name: inv.TeamName,
+			},
+			CreatedAt: inv.CreatedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) AcceptInvitation(c echo.Context) error {
+	op := "handler.AcceptInvitation"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	invitationID, ok := ExtractParamInt(c, "iid")
+	if !ok {
+		return Error(http.StatusBadRequest, "invitation ID should be an integer")
+	}
+
+	inv, err := h.repo.Team.GetInvitation(ctx, int32(invitationID))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && inv.UserID != claims.UserID) {
+		return Error(http.StatusNotFound, "invitation not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get invitation: %v", op, err)
+	}
+
+	err = h.repo.Team.AcceptInvitation(ctx, inv)
+	if errors.Is(err, team.ErrTeamTooLarge) {
+		return Error(http.StatusConflict, "team is registered for a contest, which doesn't allow more members")
+	}
+	if errors.Is(err, team.ErrAlreadyEntered) {
+		return Error(http.StatusConflict, "you are already registered for a contest the team is registered for")
+	}
+	if errors.Is(err, team.ErrRatingTooHigh) {
+		return Error(http.StatusForbidden, "team is registered for a contest, which is only for participants with lower rating")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't accept invitation: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *Handler) DeclineInvitation(c echo.Context) error {
+	op := "handler.DeclineInvitation"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	invitationID, ok := ExtractParamInt(c, "iid")
+	if !ok {
+		return Error(http.StatusBadRequest, "invitation ID should be an integer")
+	}
+
+	inv, err := h.repo.Team.GetInvitation(ctx, int32(invitationID))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && inv.UserID != claims.UserID) {
+		return Error(http.StatusNotFound, "invitation not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get invitation: %v", op, err)
+	}
+
+	if err := h.repo.Team.DeleteInvitation(ctx, inv.ID); err != nil {
+		return fmt.Errorf("%s: can't decline invitation: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/request"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/scoring"
+	"github.com/voidcontests/backend/pkg/validate"
+)
+
+func (h *Handler) CreateTemplate(c echo.Context) error {
+	op := "handler.CreateTemplate"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	var body request.CreateTemplate
+	if err := validate.Bind(c, &body); err != nil {
+		return Error(http.StatusBadRequest, "invalid body: missing required fields")
+	}
+
+	if body.DurationMins <= 0 {
+		return Error(http.StatusBadRequest, "duration should be positive")
+	}
+
+	if body.ScoringMode == "" {
+		body.ScoringMode = scoring.ModePoints
+	}
+
+	if _, err := scoring.New(body.ScoringMode); err != nil {
+		return Error(http.StatusBadRequest, "unknown scoring mode")
+	}
+
+	languages, err := normalizeLanguages(body.Languages)
+	if err != nil {
+		return err
+	}
+
+	occupied, err := h.repo.Template.IsNameOccupied(ctx, claims.UserID, body.Name)
+	if err != nil {
+		return fmt.Errorf("%s: can't verify that name isn't occupied: %v", op, err)
+	}
+	if occupied {
+		return Error(http.StatusConflict, "template name already taken")
+	}
+
+	templateID, err := h.repo.Template.Create(ctx, models.ContestTemplate{
+		OwnerID:       claims.UserID,
+		Name:          body.Name,
+		DurationMins:  body.DurationMins,
+		ScoringMode:   body.ScoringMode,
+		AllowLateJoin: body.AllowLateJoin,
+		Languages:     languages,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: can't create template: %v", op, err)
+	}
+
+	return c.JSON(http.StatusCreated, response.ID{
+		ID: templateID,
+	})
+}
+
+func (h *Handler) GetTemplates(c echo.Context) error {
+	op := "handler.GetTemplates"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	templates, err := h.repo.Template.ListByOwner(ctx, claims.UserID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get templates: %v", op, err)
+	}
+
+	items := make([]response.Template, len(templates))
+	for i, t := range templates {
+		items[i] = response.Template{
+			ID:            t.ID,
+			Name:          t.Name,
+			DurationMins:  t.DurationMins,
+			ScoringMode:   t.ScoringMode,
+			AllowLateJoin: t.AllowLateJoin,
+			Languages:     t.Languages,
+			CreatedAt:     t.CreatedAt,
+		}
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) DeleteTemplate(c echo.Context) error {
+	op := "handler.DeleteTemplate"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	templateID, ok := ExtractParamInt(c, "tid")
+	if !ok {
+		return Error(http.StatusBadRequest, "template ID should be an integer")
+	}
+
+	t, err := h.repo.Template.GetByID(ctx, int32(templateID))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && t.OwnerID != claims.UserID) {
+		return Error(http.StatusNotFound, "template not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get template: %v", op, err)
+	}
+
+	if err := h.repo.Template.Delete(ctx, t.ID); err != nil {
+		return fmt.Errorf("%s: can't delete template: %v", op, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
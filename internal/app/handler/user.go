@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/voidcontests/backend/internal/app/handler/dto/response"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+func (h *Handler) GetUser(c echo.Context) error {
+	op := "handler.GetUser"
+	ctx := c.Request().Context()
+
+	claims, _ := ExtractClaims(c)
+
+	user, err := h.repo.User.GetByUsername(ctx, c.Param("username"))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Error(http.StatusNotFound, "user not found")
+	}
+	if err != nil {
+		return fmt.Errorf("%s: can't get user: %v", op, err)
+	}
+
+	changes, err := h.repo.Rating.ListByUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("%s: can't get rating history: %v", op, err)
+	}
+
+	history := make([]response.RatingChange, len(changes))
+	for i, rc := range changes {
+		history[i] = response.RatingChange{
+			ContestID:    rc.ContestID,
+			ContestTitle: rc.ContestTitle,
+			Rank:         rc.Rank,
+			OldRating:    rc.OldRating,
+			NewRating:    rc.NewRating,
+			CreatedAt:    rc.CreatedAt,
+		}
+
+		// NOTE: rating change of private contest is kept to not break the history,
+		// but the contest itself is redacted for viewers who can't access it
+		if rc.IsPrivate {
+			accessible, err := h.canAccess(ctx, &models.Contest{ID: rc.ContestID, CreatorID: rc.CreatorID, IsPrivate: rc.IsPrivate}, claims.UserID)
+			if err != nil {
+				return fmt.Errorf("%s: can't check contest access: %v", op, err)
+			}
+			if !accessible {
+				history[i].ContestID = 0
+				history[i].ContestTitle = ""
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, response.Profile{
+		ID:            user.ID,
+		Username:      user.Username,
+		Rating:        user.Rating,
+		RatingHistory: history,
+		CreatedAt:     user.CreatedAt,
+	})
+}
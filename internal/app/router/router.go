@@ -9,6 +9,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/voidcontests/backend/internal/app/handler"
 	"github.com/voidcontests/backend/internal/config"
+	"github.com/voidcontests/backend/internal/events"
 	"github.com/voidcontests/backend/internal/lib/logger/sl"
 	"github.com/voidcontests/backend/internal/repository"
 	"github.com/voidcontests/backend/pkg/ratelimit"
@@ -21,8 +22,8 @@ type Router struct {
 	handler *handler.Handler
 }
 
-func New(c *config.Config, r *repository.Repository) *Router {
-	h := handler.New(c, r)
+func New(c *config.Config, r *repository.Repository, b *events.Broker) *Router {
+	h := handler.New(c, r, b)
 	return &Router{config: c, handler: h}
 }
 
@@ -81,22 +82,76 @@ func (r *Router) InitRoutes() *echo.Echo {
 		api.POST("/account", r.handler.CreateAccount)
 		api.POST("/session", r.handler.CreateSession)
 
+		api.GET("/users/:username", r.handler.GetUser, r.handler.TryIdentify())
+
+		api.GET("/account/calendar", r.handler.GetCalendarToken, r.handler.MustIdentify())
+		api.POST("/account/calendar", r.handler.ResetCalendarToken, r.handler.MustIdentify())
+		api.GET("/account/progress", r.handler.GetPracticeProgress, r.handler.MustIdentify())
+		api.GET("/calendar/:token/contests.ics", r.handler.GetPersonalCalendar)
+
+		api.GET("/account/invitations", r.handler.GetInvitations, r.handler.MustIdentify())
+		api.POST("/invitations/:iid/accept", r.handler.AcceptInvitation, r.handler.MustIdentify())
+		api.DELETE("/invitations/:iid", r.handler.DeclineInvitation, r.handler.MustIdentify())
+
+		api.POST("/teams", r.handler.CreateTeam, r.handler.MustIdentify())
+		api.GET("/teams/:tid", r.handler.GetTeamByID)
+		api.POST("/teams/:tid/invitations", r.handler.CreateInvitation, r.handler.MustIdentify())
+
 		api.GET("/creator/contests", r.handler.GetCreatedContests, r.handler.MustIdentify())
 		api.GET("/creator/problems", r.handler.GetCreatedProblems, r.handler.MustIdentify())
 
 		api.POST("/problems", r.handler.CreateProblem, r.handler.MustIdentify())
-
-		api.GET("/contests", r.handler.GetContests)
+		api.GET("/problems", r.handler.GetLibrary, r.handler.TryIdentify())
+		api.GET("/problems/:pid", r.handler.GetLibraryProblem, r.handler.TryIdentify())
+		api.PUT("/problems/:pid/library", r.handler.PublishProblem, r.handler.MustIdentify())
+		api.DELETE("/problems/:pid/library", r.handler.UnpublishProblem, r.handler.MustIdentify())
+		api.GET("/problems/:pid/submissions", r.handler.GetPracticeSubmissions, r.handler.MustIdentify())
+		api.POST("/problems/:pid/submissions",
+			r.handler.CreatePracticeSubmission, ratelimit.WithTimeout(5*time.Second), r.handler.MustIdentify())
+
+		api.GET("/contests", r.handler.GetContests, r.handler.TryIdentify())
+		api.GET("/contests.ics", r.handler.GetContestsCalendar)
 		api.POST("/contests", r.handler.CreateContest, r.handler.MustIdentify())
 
+		api.GET("/templates", r.handler.GetTemplates, r.handler.MustIdentify())
+		api.POST("/templates", r.handler.CreateTemplate, r.handler.MustIdentify())
+		api.DELETE("/templates/:tid", r.handler.DeleteTemplate, r.handler.MustIdentify())
+
 		api.GET("/contests/:cid", r.handler.GetContestByID, r.handler.TryIdentify())
+		api.POST("/contests/:cid/clone", r.handler.CloneContest, r.handler.MustIdentify())
 		api.POST("/contests/:cid/entry", r.handler.CreateEntry, r.handler.MustIdentify())
-		api.GET("/contests/:cid/leaderboard", r.handler.GetLeaderboard)
-
+		api.DELETE("/contests/:cid/entry", r.handler.DeleteEntry, r.handler.MustIdentify())
+		api.GET("/contests/:cid/allowlist", r.handler.GetAllowlist, r.handler.MustIdentify())
+		api.POST("/contests/:cid/allowlist", r.handler.AddToAllowlist, r.handler.MustIdentify())
+		api.DELETE("/contests/:cid/allowlist/:uid", r.handler.RemoveFromAllowlist, r.handler.MustIdentify())
+		api.GET("/contests/:cid/roles", r.handler.GetContestRoles, r.handler.MustIdentify())
+		api.PUT("/contests/:cid/roles", r.handler.SetContestRole, r.handler.MustIdentify())
+		api.DELETE("/contests/:cid/roles/:uid", r.handler.DeleteContestRole, r.handler.MustIdentify())
+		api.POST("/contests/:cid/participants/:uid/disqualification", r.handler.DisqualifyEntry, r.handler.MustIdentify())
+		api.DELETE("/contests/:cid/participants/:uid/disqualification", r.handler.ReinstateEntry, r.handler.MustIdentify())
+		api.GET("/contests/:cid/audit", r.handler.GetAuditLog, r.handler.MustIdentify())
+		api.GET("/contests/:cid/clarifications", r.handler.GetClarifications, r.handler.MustIdentify())
+		api.POST("/contests/:cid/clarifications", r.handler.CreateClarification, r.handler.MustIdentify())
+		api.POST("/contests/:cid/clarifications/:clid/answer", r.handler.AnswerClarification, r.handler.MustIdentify())
+		api.GET("/contests/:cid/announcements", r.handler.GetAnnouncements, r.handler.TryIdentify())
+		api.POST("/contests/:cid/announcements", r.handler.CreateAnnouncement, r.handler.MustIdentify())
+		api.PUT("/contests/:cid/announcements/:aid", r.handler.UpdateAnnouncement, r.handler.MustIdentify())
+		api.DELETE("/contests/:cid/announcements/:aid", r.handler.DeleteAnnouncement, r.handler.MustIdentify())
+		api.GET("/contests/:cid/events", r.handler.GetContestEvents, r.handler.MustIdentify())
+		api.GET("/contests/:cid/leaderboard", r.handler.GetLeaderboard, r.handler.TryIdentify())
+		api.GET("/contests/:cid/results.csv", r.handler.ExportResultsCSV, r.handler.MustIdentify())
+		api.GET("/contests/:cid/results.xlsx", r.handler.ExportResultsXLSX, r.handler.MustIdentify())
+		api.GET("/contests/:cid/leaderboard/live", r.handler.SubscribeLeaderboard, r.handler.TryIdentify())
+		api.POST("/contests/:cid/leaderboard/unfreeze", r.handler.UnfreezeLeaderboard, r.handler.MustIdentify())
+
+		api.PUT("/contests/:cid/problems/order", r.handler.ReorderContestProblems, r.handler.MustIdentify())
 		api.GET("/contests/:cid/problems/:charcode", r.handler.GetContestProblem, r.handler.MustIdentify())
+		api.PATCH("/contests/:cid/problems/:charcode", r.handler.UpdateContestProblem, r.handler.MustIdentify())
+		api.POST("/contests/:cid/problems/:charcode/rejudge", r.handler.RejudgeProblem, r.handler.MustIdentify())
 		api.GET("/contests/:cid/problems/:charcode/submissions", r.handler.GetSubmissions, r.handler.MustIdentify())
 		api.POST("/contests/:cid/problems/:charcode/submissions",
 			r.handler.CreateSubmission, ratelimit.WithTimeout(5*time.Second), r.handler.MustIdentify())
+		api.POST("/contests/:cid/submissions/:sid/rejudge", r.handler.RejudgeSubmission, r.handler.MustIdentify())
 		api.GET("/submissions/:sid", r.handler.GetSubmissionByID, r.handler.MustIdentify())
 	}
 
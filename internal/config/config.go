@@ -17,10 +17,12 @@ const (
 )
 
 type Config struct {
-	Env      string   `yaml:"env" env-required:"true"`
-	Server   Server   `yaml:"http" env-required:"true"`
-	Security Security `yaml:"security" env-required:"true"`
-	Postgres Postgres `yaml:"postgres" env-required:"true"`
+	Env       string    `yaml:"env" env-required:"true"`
+	Server    Server    `yaml:"http" env-required:"true"`
+	Security  Security  `yaml:"security" env-required:"true"`
+	Postgres  Postgres  `yaml:"postgres" env-required:"true"`
+	Contests  Contests  `yaml:"contests"`
+	Scheduler Scheduler `yaml:"scheduler"`
 }
 
 type Server struct {
@@ -34,6 +36,15 @@ type Security struct {
 	Salt         string `yaml:"salt" env-required:"true"`
 }
 
+type Contests struct {
+	MaxProblems int `yaml:"max_problems" env-default:"6"`
+}
+
+type Scheduler struct {
+	// Webhooks are notified about every contest lifecycle event
+	Webhooks []string `yaml:"webhooks"`
+}
+
 type Postgres struct {
 	Host     string `yaml:"host"`
 	Port     string `yaml:"port"`
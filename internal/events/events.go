@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+)
+
+// channel is a Postgres channel used to deliver events between API replicas
+const channel = "contest_events"
+
+const (
+	AnnouncementCreated   = "announcement.created"
+	AnnouncementUpdated   = "announcement.updated"
+	AnnouncementDeleted   = "announcement.deleted"
+	ClarificationAnswered = "clarification.answered"
+	// NOTE: contest lifecycle events are published by scheduler
+	ContestStarted   = "contest.started"
+	ContestFrozen    = "contest.frozen"
+	ContestEnded     = "contest.ended"
+	ContestFinalized = "contest.finalized"
+	EntryAdmitted    = "entry.admitted"
+	// NOTE: disqualification events are delivered privately to the affected participant
+	EntryDisqualified = "entry.disqualified"
+	EntryReinstated   = "entry.reinstated"
+	// NOTE: leaderboard updates are published by database trigger on submissions
+	LeaderboardUpdated = "leaderboard.updated"
+)
+
+// NOTE: Postgres limits NOTIFY payloads with 8000 bytes, so events should carry
+// only small payloads, and let clients fetch everything else with regular API.
+type Event struct {
+	Type      string `json:"type"`
+	ContestID int32  `json:"contest_id"`
+	// UserID is a recipient of a private event, zero for events visible to everyone
+	UserID    int32           `json:"user_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Broker delivers contest events to subscribers of all API replicas through Postgres LISTEN/NOTIFY
+type Broker struct {
+	pool *pgxpool.Pool
+
+	mu          sync.RWMutex
+	subscribers map[int32]map[chan Event]struct{}
+}
+
+func New(pool *pgxpool.Pool) *Broker {
+	return &Broker{
+		pool:        pool,
+		subscribers: make(map[int32]map[chan Event]struct{}),
+	}
+}
+
+// Publish sends event to subscribers of the contest on every replica
+func (b *Broker) Publish(ctx context.Context, contestID int32, userID int32, typ string, payload any) error {
+	event := Event{
+		Type:      typ,
+		ContestID: contestID,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	}
+
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("can't marshal payload: %w", err)
+		}
+		event.Payload = raw
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("can't marshal event: %w", err)
+	}
+
+	_, err = b.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, string(data))
+	return err
+}
+
+// Subscribe returns a channel with events of the contest and a function to cancel the subscription
+func (b *Broker) Subscribe(contestID int32) (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+
+	b.mu.Lock()
+	if b.subscribers[contestID] == nil {
+		b.subscribers[contestID] = make(map[chan Event]struct{})
+	}
+	b.subscribers[contestID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers[contestID], ch)
+		if len(b.subscribers[contestID]) == 0 {
+			delete(b.subscribers, contestID)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Run listens for notifications until context is canceled, reconnecting on failures
+func (b *Broker) Run(ctx context.Context) {
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Error("events: listener failed, reconnecting", sl.Err(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (b *Broker) listen(ctx context.Context) error {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("can't acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return fmt.Errorf("can't listen: %w", err)
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(n.Payload), &event); err != nil {
+			slog.Error("events: can't decode notification", sl.Err(err))
+			continue
+		}
+
+		b.dispatch(event)
+	}
+}
+
+func (b *Broker) dispatch(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.ContestID] {
+		select {
+		case ch <- event:
+		default:
+			// NOTE: slow subscribers just miss events instead of blocking everyone else
+			slog.Debug("events: subscriber is too slow, event dropped", slog.Int("contest_id", int(event.ContestID)))
+		}
+	}
+}
@@ -11,10 +11,13 @@ import (
 
 	"github.com/voidcontests/backend/internal/app/router"
 	"github.com/voidcontests/backend/internal/config"
+	"github.com/voidcontests/backend/internal/events"
 	"github.com/voidcontests/backend/internal/lib/logger/prettyslog"
 	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/rating"
 	"github.com/voidcontests/backend/internal/repository"
 	"github.com/voidcontests/backend/internal/repository/postgres"
+	"github.com/voidcontests/backend/internal/scheduler"
 )
 
 type App struct {
@@ -55,7 +58,29 @@ func (a *App) Run() {
 	slog.Info("postgresql: ok")
 
 	repo := repository.New(db)
-	r := router.New(a.config, repo)
+
+	broker := events.New(db)
+	brokerctx, stopBroker := context.WithCancel(ctx)
+	defer stopBroker()
+	go broker.Run(brokerctx)
+
+	rater := rating.NewRater(repo)
+
+	// NOTE: rating goes first, so notifications about finalization are sent only after ratings are updated
+	sched := scheduler.New(repo)
+	sched.On(events.ContestFinalized, rater.OnFinalized)
+	for _, typ := range []string{events.ContestStarted, events.ContestFrozen, events.ContestEnded, events.ContestFinalized} {
+		sched.On(typ, scheduler.Publish(broker, typ))
+		for _, url := range a.config.Scheduler.Webhooks {
+			sched.On(typ, scheduler.Webhook(url, typ))
+		}
+	}
+
+	schedctx, stopScheduler := context.WithCancel(ctx)
+	defer stopScheduler()
+	go sched.Run(schedctx)
+
+	r := router.New(a.config, repo, broker)
 
 	server := &http.Server{
 		Addr:         a.config.Server.Address,
@@ -0,0 +1,99 @@
+package rating
+
+import (
+	"math"
+	"sort"
+)
+
+// Contestant is a rated participant with the place taken in the contest.
+// Participants sharing the place should have the same rank.
+type Contestant struct {
+	UserID int32
+	Rating int32
+	Rank   int
+}
+
+// Calculate returns rating deltas of contestants, keyed by user ID.
+//
+// Every contestant expects to take a place (seed) according to Elo win probabilities against
+// all others. Rating is moved halfway to the one which would make the seed equal to the
+// geometric mean of the expected and the actual places. Deltas are then shifted to keep the
+// total rating from inflating, mostly at the expense of the top rated contestants.
+func Calculate(contestants []Contestant) map[int32]int32 {
+	n := len(contestants)
+	deltas := make(map[int32]int32, n)
+	if n < 2 {
+		return deltas
+	}
+
+	ratings := make([]float64, n)
+	for i, c := range contestants {
+		ratings[i] = float64(c.Rating)
+	}
+
+	raw := make([]float64, n)
+	for i, c := range contestants {
+		// NOTE: contestant's own rating is excluded from the expected place
+		seed := expectedPlace(ratings, ratings[i]) - 0.5
+		mid := math.Sqrt(seed * float64(c.Rank))
+		raw[i] = (ratingForPlace(ratings, mid) - ratings[i]) / 2
+	}
+
+	var sum float64
+	for _, d := range raw {
+		sum += d
+	}
+	inc := -sum/float64(n) - 1
+	for i := range raw {
+		raw[i] += inc
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return ratings[order[a]] > ratings[order[b]]
+	})
+
+	top := min(n, int(4*math.Sqrt(float64(n))))
+	var topSum float64
+	for _, i := range order[:top] {
+		topSum += raw[i]
+	}
+	inc = math.Min(math.Max(-topSum/float64(top), -10), 0)
+
+	for i, c := range contestants {
+		deltas[c.UserID] = int32(math.Round(raw[i] + inc))
+	}
+
+	return deltas
+}
+
+// winProbability is a probability of a contestant with rating `a` to outperform one with rating `b`
+func winProbability(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// expectedPlace returns 1-based place expected for the rating among all ratings
+func expectedPlace(ratings []float64, rating float64) float64 {
+	place := 1.0
+	for _, r := range ratings {
+		place += winProbability(r, rating)
+	}
+	return place
+}
+
+// ratingForPlace finds rating which expected place is the closest to the provided one
+func ratingForPlace(ratings []float64, place float64) float64 {
+	lo, hi := 1.0, 8000.0
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if expectedPlace(ratings, mid) < place {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return lo
+}
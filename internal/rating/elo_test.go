@@ -0,0 +1,78 @@
+package rating
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name        string
+		contestants []Contestant
+		want        map[int32]int32
+	}{
+		{"no contestants", nil, map[int32]int32{}},
+		{"single contestant", []Contestant{{UserID: 1, Rating: 1500, Rank: 1}}, map[int32]int32{}},
+		{
+			"equal ratings",
+			[]Contestant{{UserID: 1, Rating: 1500, Rank: 1}, {UserID: 2, Rating: 1500, Rank: 2}},
+			map[int32]int32{1: 65, 2: -67},
+		},
+		{
+			"tie in rank",
+			[]Contestant{{UserID: 1, Rating: 1500, Rank: 1}, {UserID: 2, Rating: 1500, Rank: 1}},
+			map[int32]int32{1: -1, 2: -1},
+		},
+		{
+			"shared place",
+			[]Contestant{{UserID: 1, Rating: 1500, Rank: 1}, {UserID: 2, Rating: 1500, Rank: 1}, {UserID: 3, Rating: 1500, Rank: 3}},
+			map[int32]int32{1: 50, 2: 50, 3: -103},
+		},
+		{
+			"upset",
+			[]Contestant{{UserID: 1, Rating: 2000, Rank: 2}, {UserID: 2, Rating: 1200, Rank: 1}},
+			map[int32]int32{1: -203, 2: 201},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(tt.contestants)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Calculate() = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("Calculate()[%d] = %d, want %d", id, got[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateDrift(t *testing.T) {
+	for _, n := range []int{2, 5, 10, 50, 200} {
+		t.Run(fmt.Sprintf("%d contestants", n), func(t *testing.T) {
+			contestants := make([]Contestant, n)
+			for i := range contestants {
+				// NOTE: ratings are spread without correlation to the places, every third place is shared
+				contestants[i] = Contestant{
+					UserID: int32(i + 1),
+					Rating: int32(1000 + i*37%900),
+					Rank:   i - i%3 + 1,
+				}
+			}
+
+			var total int
+			for _, d := range Calculate(contestants) {
+				total += int(d)
+			}
+
+			// NOTE: total rating never inflates, and deflates by at most 1 per contestant
+			// plus 10 per contestant for the top rated adjustment
+			if total > 0 || total < -11*n {
+				t.Errorf("total delta = %d, want within [%d, 0]", total, -11*n)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/voidcontests/backend/internal/repository"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/standings"
+)
+
+// Rater recalculates ratings of participants once results of rated contests are final
+type Rater struct {
+	repo *repository.Repository
+}
+
+func NewRater(repo *repository.Repository) *Rater {
+	return &Rater{repo}
+}
+
+// OnFinalized is a scheduler hook, which rates the contest once its results are final
+func (r *Rater) OnFinalized(ctx context.Context, contest *models.Contest) error {
+	if !contest.IsRated || contest.RatedAt != nil {
+		return nil
+	}
+	return r.Rate(ctx, contest.ID)
+}
+
+// Rate calculates rating changes from the final standings of the contest and applies them
+func (r *Rater) Rate(ctx context.Context, contestID int32) error {
+	contest, err := r.repo.Contest.GetByID(ctx, contestID)
+	if err != nil {
+		return fmt.Errorf("can't get contest: %w", err)
+	}
+
+	rows, err := standings.Compute(ctx, r.repo, contest, false, 0)
+	if err != nil {
+		return fmt.Errorf("can't compute standings: %w", err)
+	}
+
+	cs, err := r.repo.Rating.ListContestants(ctx, contest.ID)
+	if err != nil {
+		return fmt.Errorf("can't get contestants: %w", err)
+	}
+
+	ratings := make(map[int32]int32, len(cs))
+	for _, c := range cs {
+		ratings[c.EntryID] = c.Rating
+	}
+
+	// NOTE: places are recalculated among contestants only, and participants
+	// sharing the place take the lowest one of the range
+	var contestants []Contestant
+	for _, row := range rows {
+		rating, ok := ratings[row.EntryID]
+		if !ok {
+			continue
+		}
+		contestants = append(contestants, Contestant{UserID: row.UserID, Rating: rating, Rank: row.Rank})
+	}
+
+	ranks := make(map[int32]int32, len(contestants))
+	for i := 0; i < len(contestants); {
+		j := i
+		for j+1 < len(contestants) && contestants[j+1].Rank == contestants[i].Rank {
+			j++
+		}
+		for k := i; k <= j; k++ {
+			contestants[k].Rank = j + 1
+			ranks[contestants[k].UserID] = int32(j + 1)
+		}
+		i = j + 1
+	}
+
+	if err := r.repo.Rating.Apply(ctx, contest.ID, ranks, Calculate(contestants)); err != nil {
+		return fmt.Errorf("can't apply rating changes: %w", err)
+	}
+
+	slog.Info("rating: contest rated", slog.Int("contest_id", int(contest.ID)), slog.Int("contestants", len(contestants)))
+	return nil
+}
@@ -9,6 +9,27 @@ const (
 	RoleBanned    = "banned"
 )
 
+// NOTE: contest roles are granted by contest creator to other users,
+// creator itself has every permission without any role
+const (
+	ContestRoleCoAuthor = "co_author"
+	ContestRoleTester   = "tester"
+	ContestRoleManager  = "manager"
+)
+
+const (
+	LeaderboardPublic       = "public"
+	LeaderboardParticipants = "participants"
+	// LeaderboardAfterEnd hides standings from everyone but contest staff until the contest end
+	LeaderboardAfterEnd = "after_end"
+	LeaderboardHidden   = "hidden"
+)
+
+const (
+	AuditDisqualified = "entry.disqualified"
+	AuditReinstated   = "entry.reinstated"
+)
+
 const (
 	TextAnswerProblem = "text_answer_problem"
 	CodingProblem     = "coding_problem"
@@ -19,6 +40,7 @@ type User struct {
 	Username     string    `db:"username"`
 	PasswordHash string    `db:"password_hash"`
 	RoleID       int32     `db:"role_id"`
+	Rating       int32     `db:"rating"`
 	CreatedAt    time.Time `db:"created_at"`
 }
 
@@ -32,32 +54,61 @@ type Role struct {
 }
 
 type Contest struct {
-	ID              int32     `db:"id"`
-	CreatorID       int32     `db:"creator_id"`
-	CreatorUsername string    `db:"creator_username"`
-	Title           string    `db:"title"`
-	Description     string    `db:"description"`
-	StartTime       time.Time `db:"start_time"`
-	EndTime         time.Time `db:"end_time"`
-	DurationMins    int32     `db:"duration_mins"`
-	MaxEntries      int32     `db:"max_entries"`
-	AllowLateJoin   bool      `db:"allow_late_join"`
-	Participants    int32     `db:"participants"`
-	CreatedAt       time.Time `db:"created_at"`
+	ID              int32      `db:"id"`
+	CreatorID       int32      `db:"creator_id"`
+	CreatorUsername string     `db:"creator_username"`
+	Title           string     `db:"title"`
+	Description     string     `db:"description"`
+	StartTime       time.Time  `db:"start_time"`
+	EndTime         time.Time  `db:"end_time"`
+	DurationMins    int32      `db:"duration_mins"`
+	MaxEntries      int32      `db:"max_entries"`
+	AllowLateJoin   bool       `db:"allow_late_join"`
+	ScoringMode     string     `db:"scoring_mode"`
+	FreezeMins      int32      `db:"freeze_mins"`
+	Unfrozen        bool       `db:"unfrozen"`
+	MaxTeamSize     int32      `db:"max_team_size"`
+	IsPrivate       bool       `db:"is_private"`
+	InviteCode      string     `db:"invite_code"`
+	IsRated         bool       `db:"is_rated"`
+	MaxRating       int32      `db:"max_rating"`
+	RatedAt         *time.Time `db:"rated_at"`
+	Languages       []string   `db:"languages"`
+	// LeaderboardVisibility controls who could see standings, besides contest staff
+	LeaderboardVisibility string    `db:"leaderboard_visibility"`
+	Participants          int32     `db:"participants"`
+	CreatedAt             time.Time `db:"created_at"`
+}
+
+// ContestProblem is a problem of the contest, labeled with its charcode
+type ContestProblem struct {
+	ProblemID int32  `db:"problem_id"`
+	Charcode  string `db:"charcode"`
+	Points    int32  `db:"points"`
 }
 
 type Problem struct {
-	ID             int32     `db:"id"`
-	Charcode       string    `db:"charcode"`
-	Kind           string    `db:"kind"`
-	WriterID       int32     `db:"writer_id"`
-	WriterUsername string    `db:"writer_username"`
-	Title          string    `db:"title"`
-	Statement      string    `db:"statement"`
-	Difficulty     string    `db:"difficulty"`
-	Answer         string    `db:"answer"`
-	TimeLimitMS    int32     `db:"time_limit_ms"`
-	CreatedAt      time.Time `db:"created_at"`
+	ID             int32  `db:"id"`
+	Charcode       string `db:"charcode"`
+	Kind           string `db:"kind"`
+	WriterID       int32  `db:"writer_id"`
+	WriterUsername string `db:"writer_username"`
+	Title          string `db:"title"`
+	Statement      string `db:"statement"`
+	Difficulty     string `db:"difficulty"`
+	Points         int32  `db:"points"`
+	Revealed       bool   `db:"revealed"`
+	Answer         string `db:"answer"`
+	TimeLimitMS    int32  `db:"time_limit_ms"`
+	TestsCount     int32  `db:"tests_count"`
+	// NOTE: public problems are listed in the problem library
+	IsPublic    bool       `db:"is_public"`
+	Tags        []string   `db:"tags"`
+	Rating      int32      `db:"rating"`
+	PublishedAt *time.Time `db:"published_at"`
+	// Solved reports whether the problem is solved by the viewer
+	Solved    bool      `db:"solved"`
+	CreatedAt time.Time `db:"created_at"`
 }
 
 type TestCase struct {
@@ -69,15 +120,21 @@ type TestCase struct {
 }
 
 type Entry struct {
-	ID        int32     `db:"id"`
-	ContestID int32     `db:"contest_id"`
-	UserID    int32     `db:"user_id"`
-	CreatedAt time.Time `db:"created_at"`
+	ID        int32  `db:"id"`
+	ContestID int32  `db:"contest_id"`
+	UserID    int32  `db:"user_id"`
+	TeamID    *int32 `db:"team_id"`
+	Upsolving bool   `db:"upsolving"`
+	// NOTE: disqualified entries are hidden from the leaderboard and couldn't submit anymore
+	DisqualifiedAt         *time.Time `db:"disqualified_at"`
+	DisqualificationReason string     `db:"disqualification_reason"`
+	CreatedAt              time.Time  `db:"created_at"`
 }
 
 type Submission struct {
 	ID               int32     `db:"id"`
-	EntryID          int32     `db:"entry_id"`
+	EntryID          *int32    `db:"entry_id"`
+	UserID           *int32    `db:"user_id"` // NOTE: only practice submissions have user and no entry
 	ProblemID        int32     `db:"problem_id"`
 	ProblemKind      string    `db:"problem_kind"`
 	Verdict          string    `db:"verdict"`
@@ -86,14 +143,101 @@ type Submission struct {
 	Language         string    `db:"language"`
 	PassedTestsCount int32     `db:"passed_tests_count"`
 	Stderr           string    `db:"stderr"`
+	Upsolving        bool      `db:"upsolving"`
 	CreatedAt        time.Time `db:"created_at"`
 	// NOTE: locked_at is invisible fields in models, because it is never used outside of database.
 }
 
-type LeaderboardEntry struct {
-	UserID   int32  `db:"user_id" json:"user_id"`
-	Username string `db:"username" json:"username"`
-	Points   int    `db:"points" json:"points"`
+type Participant struct {
+	EntryID  int32  `db:"entry_id"`
+	UserID   int32  `db:"user_id"`
+	Username string `db:"username"`
+	TeamID   *int32 `db:"team_id"`
+	TeamName string `db:"team_name"`
+}
+
+type Team struct {
+	ID        int32     `db:"id"`
+	Name      string    `db:"name"`
+	CaptainID int32     `db:"captain_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type TeamInvitation struct {
+	ID        int32     `db:"id"`
+	TeamID    int32     `db:"team_id"`
+	TeamName  string    `db:"team_name"`
+	UserID    int32     `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type Clarification struct {
+	ID         int32      `db:"id"`
+	ContestID  int32      `db:"contest_id"`
+	ProblemID  *int32     `db:"problem_id"`
+	Charcode   string     `db:"charcode"`
+	UserID     int32      `db:"user_id"`
+	Username   string     `db:"username"`
+	Question   string     `db:"question"`
+	Answer     string     `db:"answer"`
+	IsPublic   bool       `db:"is_public"`
+	AnsweredAt *time.Time `db:"answered_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+type Announcement struct {
+	ID        int32     `db:"id"`
+	ContestID int32     `db:"contest_id"`
+	AuthorID  int32     `db:"author_id"`
+	Text      string    `db:"text"`
+	UpdatedAt time.Time `db:"updated_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Standing is a materialized result of an entry on a single problem, maintained by
+// database trigger on submissions. Frozen fields describe the same result as it
+// is visible during the leaderboard freeze.
+type Standing struct {
+	EntryID          int32      `db:"entry_id"`
+	ProblemID        int32      `db:"problem_id"`
+	Attempts         int32      `db:"attempts"`
+	AcceptedAt       *time.Time `db:"accepted_at"`
+	BestPassed       int32      `db:"best_passed"`
+	Pending          int32      `db:"pending"`
+	FrozenAttempts   int32      `db:"frozen_attempts"`
+	FrozenAcceptedAt *time.Time `db:"frozen_accepted_at"`
+	FrozenBestPassed int32      `db:"frozen_best_passed"`
+	FrozenPending    int32      `db:"frozen_pending"`
+}
+
+type ContestTemplate struct {
+	ID            int32     `db:"id"`
+	OwnerID       int32     `db:"owner_id"`
+	Name          string    `db:"name"`
+	DurationMins  int32     `db:"duration_mins"`
+	ScoringMode   string    `db:"scoring_mode"`
+	AllowLateJoin bool      `db:"allow_late_join"`
+	Languages     []string  `db:"languages"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// Contestant is a participant whose rating is affected by the contest
+type Contestant struct {
+	EntryID int32 `db:"entry_id"`
+	UserID  int32 `db:"user_id"`
+	Rating  int32 `db:"rating"`
+}
+
+type RatingChange struct {
+	ContestID    int32     `db:"contest_id"`
+	ContestTitle string    `db:"contest_title"`
+	CreatorID    int32     `db:"creator_id"`
+	IsPrivate    bool      `db:"is_private"`
+	UserID       int32     `db:"user_id"`
+	Rank         int32     `db:"rank"`
+	OldRating    int32     `db:"old_rating"`
+	NewRating    int32     `db:"new_rating"`
+	CreatedAt    time.Time `db:"created_at"`
 }
 
 type FailedTest struct {
@@ -104,3 +248,30 @@ type FailedTest struct {
 	ActualOutput   string    `db:"actual_output"`
 	CreatedAt      time.Time `db:"created_at"`
 }
+
+type ContestRole struct {
+	ContestID int32     `db:"contest_id"`
+	UserID    int32     `db:"user_id"`
+	Username  string    `db:"username"`
+	Role      string    `db:"role"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type LifecycleEvent struct {
+	ContestID int32     `db:"contest_id"`
+	Type      string    `db:"type"`
+	DueAt     time.Time `db:"due_at"`
+}
+
+// AuditRecord is an action of contest staff, which affected participants
+type AuditRecord struct {
+	ID             int32     `db:"id"`
+	ContestID      int32     `db:"contest_id"`
+	ActorID        int32     `db:"actor_id"`
+	ActorUsername  string    `db:"actor_username"`
+	Action         string    `db:"action"`
+	TargetID       *int32    `db:"target_id"`
+	TargetUsername string    `db:"target_username"`
+	Details        string    `db:"details"`
+	CreatedAt      time.Time `db:"created_at"`
+}
@@ -0,0 +1,69 @@
+package announcement
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+func (p *Postgres) Create(ctx context.Context, contestID, authorID int32, text string) (models.Announcement, error) {
+	query := `INSERT INTO announcements (contest_id, author_id, text) VALUES ($1, $2, $3)
+		RETURNING id, contest_id, author_id, text, updated_at, created_at`
+
+	var a models.Announcement
+	err := p.pool.QueryRow(ctx, query, contestID, authorID, text).Scan(&a.ID, &a.ContestID, &a.AuthorID, &a.Text, &a.UpdatedAt, &a.CreatedAt)
+	return a, err
+}
+
+func (p *Postgres) GetByID(ctx context.Context, announcementID int32) (models.Announcement, error) {
+	query := `SELECT id, contest_id, author_id, text, updated_at, created_at FROM announcements WHERE id = $1`
+
+	var a models.Announcement
+	err := p.pool.QueryRow(ctx, query, announcementID).Scan(&a.ID, &a.ContestID, &a.AuthorID, &a.Text, &a.UpdatedAt, &a.CreatedAt)
+	return a, err
+}
+
+func (p *Postgres) ListByContest(ctx context.Context, contestID int32) ([]models.Announcement, error) {
+	query := `SELECT id, contest_id, author_id, text, updated_at, created_at FROM announcements
+		WHERE contest_id = $1 ORDER BY created_at DESC`
+
+	rows, err := p.pool.Query(ctx, query, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var announcements []models.Announcement
+	for rows.Next() {
+		var a models.Announcement
+		if err := rows.Scan(&a.ID, &a.ContestID, &a.AuthorID, &a.Text, &a.UpdatedAt, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, a)
+	}
+
+	return announcements, rows.Err()
+}
+
+func (p *Postgres) Update(ctx context.Context, announcementID int32, text string) (models.Announcement, error) {
+	query := `UPDATE announcements SET text = $2, updated_at = now() WHERE id = $1
+		RETURNING id, contest_id, author_id, text, updated_at, created_at`
+
+	var a models.Announcement
+	err := p.pool.QueryRow(ctx, query, announcementID, text).Scan(&a.ID, &a.ContestID, &a.AuthorID, &a.Text, &a.UpdatedAt, &a.CreatedAt)
+	return a, err
+}
+
+func (p *Postgres) Delete(ctx context.Context, announcementID int32) error {
+	_, err := p.pool.Exec(ctx, `DELETE FROM announcements WHERE id = $1`, announcementID)
+	return err
+}
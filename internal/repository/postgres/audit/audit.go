@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+// ListByContest returns audit log of the contest, the latest records go first
+func (p *Postgres) ListByContest(ctx context.Context, contestID int32) ([]models.AuditRecord, error) {
+	query := `SELECT a.id, a.contest_id, a.actor_id, actor.username, a.action, a.target_id, COALESCE(target.username, ''), a.details, a.created_at
+		FROM contest_audit_log a
+		JOIN users actor ON actor.id = a.actor_id
+		LEFT JOIN users target ON target.id = a.target_id
+		WHERE a.contest_id = $1
+		ORDER BY a.created_at DESC, a.id DESC`
+
+	rows, err := p.pool.Query(ctx, query, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []models.AuditRecord
+	for rows.Next() {
+		var r models.AuditRecord
+		if err := rows.Scan(&r.ID, &r.ContestID, &r.ActorID, &r.ActorUsername, &r.Action, &r.TargetID, &r.TargetUsername, &r.Details, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, rows.Err()
+}
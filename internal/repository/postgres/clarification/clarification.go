@@ -0,0 +1,110 @@
+package clarification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+func (p *Postgres) Create(ctx context.Context, contestID int32, problemID *int32, userID int32, question string) (int32, error) {
+	var id int32
+	query := `INSERT INTO clarifications (contest_id, problem_id, user_id, question) VALUES ($1, $2, $3, $4) RETURNING id`
+	err := p.pool.QueryRow(ctx, query, contestID, problemID, userID, question).Scan(&id)
+	return id, err
+}
+
+func (p *Postgres) GetByID(ctx context.Context, clarificationID int32) (models.Clarification, error) {
+	query := `SELECT cl.id, cl.contest_id, cl.problem_id, COALESCE(cp.charcode, '') AS charcode, cl.user_id, u.username,
+			cl.question, cl.answer, cl.is_public, cl.answered_at, cl.created_at
+		FROM clarifications cl
+		JOIN users u ON u.id = cl.user_id
+		LEFT JOIN contest_problems cp ON cp.contest_id = cl.contest_id AND cp.problem_id = cl.problem_id
+		WHERE cl.id = $1`
+
+	var cl models.Clarification
+	err := p.pool.QueryRow(ctx, query, clarificationID).Scan(
+		&cl.ID, &cl.ContestID, &cl.ProblemID, &cl.Charcode, &cl.UserID, &cl.Username,
+		&cl.Question, &cl.Answer, &cl.IsPublic, &cl.AnsweredAt, &cl.CreatedAt,
+	)
+	return cl, err
+}
+
+func (p *Postgres) Answer(ctx context.Context, clarificationID int32, answer string, public bool) error {
+	query := `UPDATE clarifications SET answer = $2, is_public = $3, answered_at = now() WHERE id = $1`
+	_, err := p.pool.Exec(ctx, query, clarificationID, answer, public)
+	return err
+}
+
+// ListAll returns all clarifications of the contest, for contest owners
+func (p *Postgres) ListAll(ctx context.Context, contestID int32) ([]models.Clarification, error) {
+	return p.list(ctx, `cl.contest_id = $1`, contestID)
+}
+
+// ListRelevant returns clarifications asked by the user and all public ones
+func (p *Postgres) ListRelevant(ctx context.Context, contestID, userID int32) ([]models.Clarification, error) {
+	return p.list(ctx, `cl.contest_id = $1 AND (cl.user_id = $2 OR cl.is_public)`, contestID, userID)
+}
+
+func (p *Postgres) list(ctx context.Context, filter string, args ...any) ([]models.Clarification, error) {
+	query := `SELECT cl.id, cl.contest_id, cl.problem_id, COALESCE(cp.charcode, '') AS charcode, cl.user_id, u.username,
+			cl.question, cl.answer, cl.is_public, cl.answered_at, cl.created_at
+		FROM clarifications cl
+		JOIN users u ON u.id = cl.user_id
+		LEFT JOIN contest_problems cp ON cp.contest_id = cl.contest_id AND cp.problem_id = cl.problem_id
+		WHERE ` + filter + `
+		ORDER BY cl.created_at DESC`
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var clarifications []models.Clarification
+	for rows.Next() {
+		var cl models.Clarification
+		if err := rows.Scan(
+			&cl.ID, &cl.ContestID, &cl.ProblemID, &cl.Charcode, &cl.UserID, &cl.Username,
+			&cl.Question, &cl.Answer, &cl.IsPublic, &cl.AnsweredAt, &cl.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		clarifications = append(clarifications, cl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return clarifications, nil
+}
+
+// CountUnread returns amount of answers relevant to the user, which were given after user's last read
+func (p *Postgres) CountUnread(ctx context.Context, contestID, userID int32) (int, error) {
+	query := `SELECT COUNT(*)
+		FROM clarifications cl
+		LEFT JOIN clarification_reads r ON r.contest_id = cl.contest_id AND r.user_id = $2
+		WHERE cl.contest_id = $1 AND (cl.user_id = $2 OR cl.is_public)
+			AND cl.answered_at IS NOT NULL AND (r.read_at IS NULL OR cl.answered_at > r.read_at)`
+
+	var count int
+	err := p.pool.QueryRow(ctx, query, contestID, userID).Scan(&count)
+	return count, err
+}
+
+func (p *Postgres) MarkRead(ctx context.Context, contestID, userID int32) error {
+	query := `INSERT INTO clarification_reads (contest_id, user_id) VALUES ($1, $2)
+		ON CONFLICT (contest_id, user_id) DO UPDATE SET read_at = now()`
+	_, err := p.pool.Exec(ctx, query, contestID, userID)
+	return err
+}
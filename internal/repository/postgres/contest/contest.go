@@ -2,6 +2,7 @@ package contest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -13,6 +14,46 @@ import (
 
 const defaultLimit = 20
 
+const (
+	// StatusActive matches contests which are not finished yet
+	StatusActive   = "active"
+	StatusUpcoming = "upcoming"
+	StatusRunning  = "running"
+	StatusPast     = "past"
+	StatusAll      = "all"
+)
+
+const (
+	SortStartTime    = "start_time"
+	SortParticipants = "participants"
+)
+
+// Filter describes contests listing. Private contests are listed only for
+// `mine` and `participating` filters, which require user ID.
+type Filter struct {
+	Status        string
+	UserID        int32
+	Mine          bool
+	Participating bool
+	// Query is searched in contest title and description
+	Query string
+	// Sort is one of sort keys, default order depends on status
+	Sort string
+	Desc bool
+	// Cursor is the last contest of the previous page, if provided offset is ignored
+	Cursor *Cursor
+	Limit  int
+	Offset int
+}
+
+// Cursor holds sort keys of the last listed contest
+type Cursor struct {
+	ID           int32     `json:"id"`
+	StartTime    time.Time `json:"start_time"`
+	EndTime      time.Time `json:"end_time"`
+	Participants int32     `json:"participants"`
+}
+
 type Postgres struct {
 	pool *pgxpool.Pool
 }
@@ -29,48 +70,45 @@ func (p *Postgres) Create(ctx context.Context, creatorID int32, title, descripti
 	return id, err
 }
 
-func (p *Postgres) CreateWithProblemIDs(ctx context.Context, creatorID int32, title, desc string, startTime, endTime time.Time, durationMins, maxEntries int32, allowLateJoin bool, problemIDs []int32) (int32, error) {
-	charcodes := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-	if len(problemIDs) > len(charcodes) {
-		return 0, fmt.Errorf("not enough charcodes for the number of problems")
-	}
-
+// CreateWithProblems creates the contest with its problems in the provided order.
+// NOTE: ID and other computed fields of the contest are ignored.
+func (p *Postgres) CreateWithProblems(ctx context.Context, c models.Contest, problems []models.ContestProblem) (int32, error) {
 	tx, err := p.pool.Begin(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	batch := &pgx.Batch{}
-
-	batch.Queue(
+	var contestID int32
+	err = tx.QueryRow(ctx,
 		`INSERT INTO contests
-		(creator_id, title, description, start_time, end_time, duration_mins, max_entries, allow_late_join)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		(creator_id, title, description, start_time, end_time, duration_mins, max_entries, allow_late_join, scoring_mode, freeze_mins, max_team_size, is_private, invite_code, is_rated, max_rating, languages, leaderboard_visibility)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		RETURNING id`,
-		creatorID, title, desc, startTime, endTime, durationMins, maxEntries, allowLateJoin,
-	)
+		c.CreatorID, c.Title, c.Description, c.StartTime, c.EndTime, c.DurationMins, c.MaxEntries, c.AllowLateJoin, c.ScoringMode, c.FreezeMins, c.MaxTeamSize, c.IsPrivate, c.InviteCode, c.IsRated, c.MaxRating, c.Languages, c.LeaderboardVisibility,
+	).Scan(&contestID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert contest: %w", err)
+	}
 
-	for i, id := range problemIDs {
+	batch := &pgx.Batch{}
+	for i, problem := range problems {
 		batch.Queue(
-			`INSERT INTO contest_problems (contest_id, problem_id, charcode) VALUES ($1, $2, $3)`,
-			nil, id, string(charcodes[i]),
+			`INSERT INTO contest_problems (contest_id, problem_id, charcode, points, position) VALUES ($1, $2, $3, $4, $5)`,
+			contestID, problem.ProblemID, problem.Charcode, problem.Points, i,
 		)
 	}
 
 	br := tx.SendBatch(ctx, batch)
-	defer br.Close()
-
-	var contestID int32
-	if err := br.QueryRow().Scan(&contestID); err != nil {
-		return 0, fmt.Errorf("failed to insert contest: %w", err)
-	}
-
-	for i := 0; i < len(problemIDs); i++ {
+	for i := 0; i < len(problems); i++ {
 		if _, err := br.Exec(); err != nil {
+			br.Close()
 			return 0, fmt.Errorf("problem insert %d failed: %w", i, err)
 		}
 	}
+	if err := br.Close(); err != nil {
+		return 0, fmt.Errorf("close batch: %w", err)
+	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return 0, fmt.Errorf("commit failed: %w", err)
@@ -81,13 +119,13 @@ func (p *Postgres) CreateWithProblemIDs(ctx context.Context, creatorID int32, ti
 
 func (p *Postgres) GetByID(ctx context.Context, contestID int32) (*models.Contest, error) {
 	var contest models.Contest
-	query := `SELECT contests.*, users.username AS creator_username, COUNT(entries.id) AS participants
+	query := `SELECT contests.*, users.username AS creator_username, COUNT(entries.id) FILTER (WHERE NOT entries.upsolving) AS participants
 		FROM contests
 		JOIN users ON users.id = contests.creator_id
 		LEFT JOIN entries ON entries.contest_id = contests.id
 		WHERE contests.id = $1
 		GROUP BY contests.id, users.username`
-	err := p.pool.QueryRow(ctx, query, contestID).Scan(&contest.ID, &contest.CreatorID, &contest.Title, &contest.Description, &contest.StartTime, &contest.EndTime, &contest.DurationMins, &contest.MaxEntries, &contest.AllowLateJoin, &contest.CreatedAt, &contest.CreatorUsername, &contest.Participants)
+	err := p.pool.QueryRow(ctx, query, contestID).Scan(&contest.ID, &contest.CreatorID, &contest.Title, &contest.Description, &contest.StartTime, &contest.EndTime, &contest.DurationMins, &contest.MaxEntries, &contest.AllowLateJoin, &contest.CreatedAt, &contest.ScoringMode, &contest.FreezeMins, &contest.Unfrozen, &contest.MaxTeamSize, &contest.IsPrivate, &contest.InviteCode, &contest.IsRated, &contest.MaxRating, &contest.RatedAt, &contest.Languages, &contest.LeaderboardVisibility, &contest.CreatorUsername, &contest.Participants)
 	if err != nil {
 		return nil, err
 	}
@@ -95,11 +133,12 @@ func (p *Postgres) GetByID(ctx context.Context, contestID int32) (*models.Contes
 }
 
 func (p *Postgres) GetProblemset(ctx context.Context, contestID int32) ([]models.Problem, error) {
-	query := `SELECT cp.charcode, p.*, u.username AS writer_username
+	query := `SELECT cp.charcode, cp.points, cp.revealed, p.id, p.kind, p.writer_id, p.title, p.statement, p.difficulty, p.answer, p.time_limit_ms, p.created_at, u.username AS writer_username,
+			(SELECT COUNT(*) FROM test_cases tc WHERE tc.problem_id = p.id) AS tests_count
 		FROM problems p
 		JOIN contest_problems cp ON p.id = cp.problem_id
 		JOIN users u ON u.id = p.writer_id
-		WHERE cp.contest_id = $1 ORDER BY charcode ASC`
+		WHERE cp.contest_id = $1 ORDER BY cp.position ASC, cp.charcode ASC`
 
 	rows, err := p.pool.Query(ctx, query, contestID)
 	if err != nil {
@@ -110,7 +149,7 @@ func (p *Postgres) GetProblemset(ctx context.Context, contestID int32) ([]models
 	var problems []models.Problem
 	for rows.Next() {
 		var problem models.Problem
-		if err := rows.Scan(&problem.Charcode, &problem.ID, &problem.Kind, &problem.WriterID, &problem.Title, &problem.Statement, &problem.Difficulty, &problem.Answer, &problem.TimeLimitMS, &problem.CreatedAt, &problem.WriterUsername); err != nil {
+		if err := rows.Scan(&problem.Charcode, &problem.Points, &problem.Revealed, &problem.ID, &problem.Kind, &problem.WriterID, &problem.Title, &problem.Statement, &problem.Difficulty, &problem.Answer, &problem.TimeLimitMS, &problem.CreatedAt, &problem.WriterUsername, &problem.TestsCount); err != nil {
 			return nil, err
 		}
 		problems = append(problems, problem)
@@ -118,25 +157,123 @@ func (p *Postgres) GetProblemset(ctx context.Context, contestID int32) ([]models
 	return problems, nil
 }
 
-func (p *Postgres) ListAll(ctx context.Context, limit int, offset int) (contests []models.Contest, total int, err error) {
-	if limit < 0 {
-		limit = defaultLimit
+func (p *Postgres) ListAll(ctx context.Context, f Filter) (contests []models.Contest, total int, err error) {
+	if f.Limit < 0 {
+		f.Limit = defaultLimit
+	}
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var where []string
+	switch f.Status {
+	case StatusUpcoming:
+		where = append(where, `contests.start_time > now()`)
+	case StatusRunning:
+		where = append(where, `contests.start_time <= now() AND contests.end_time >= now()`)
+	case StatusPast:
+		where = append(where, `contests.end_time < now()`)
+	case StatusAll:
+	default:
+		where = append(where, `contests.end_time >= now()`)
+	}
+
+	// NOTE: private contests are listed only to users having access to them
+	if f.Mine || f.Participating {
+		u := arg(f.UserID)
+		if f.Mine {
+			where = append(where, `contests.creator_id = `+u)
+		}
+		if f.Participating {
+			where = append(where, `EXISTS (SELECT 1 FROM entries e WHERE e.contest_id = contests.id AND NOT e.upsolving
+				AND (e.user_id = `+u+` OR e.team_id IN (SELECT team_id FROM team_members WHERE user_id = `+u+`)))`)
+		}
+	} else {
+		where = append(where, `NOT contests.is_private`)
+	}
+
+	if f.Query != "" {
+		q := arg("%" + escapeLike(f.Query) + "%")
+		where = append(where, `(contests.title ILIKE `+q+` OR contests.description ILIKE `+q+`)`)
+	}
+
+	// NOTE: contests are always ordered by ID at last, to make cursor unambiguous
+	var key string
+	var value any
+	desc := f.Desc
+	switch f.Sort {
+	case SortStartTime:
+		key = `contests.start_time`
+		if f.Cursor != nil {
+			value = f.Cursor.StartTime
+		}
+	case SortParticipants:
+		key = `COUNT(entries.id) FILTER (WHERE NOT entries.upsolving)`
+		if f.Cursor != nil {
+			value = f.Cursor.Participants
+		}
+	default:
+		if f.Status == StatusPast {
+			key = `contests.end_time`
+			desc = true
+			if f.Cursor != nil {
+				value = f.Cursor.EndTime
+			}
+		}
+	}
+
+	direction, cmp := "ASC", ">"
+	if desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	filter := strings.Join(where, " AND ")
+	countArgs := append([]any(nil), args...)
+
+	var seek, having string
+	if f.Cursor != nil {
+		var cond string
+		if key == "" {
+			cond = `contests.id ` + cmp + ` ` + arg(f.Cursor.ID)
+		} else {
+			v := arg(value)
+			cond = `(` + key + `, contests.id) ` + cmp + ` (` + v + `, ` + arg(f.Cursor.ID) + `)`
+		}
+
+		if f.Sort == SortParticipants {
+			having = `HAVING ` + cond
+		} else {
+			seek = ` AND ` + cond
+		}
+	}
+
+	order := `contests.id ` + direction
+	if key != "" {
+		order = key + ` ` + direction + `, ` + order
+	}
+
+	offset := f.Offset
+	if f.Cursor != nil {
+		offset = 0
 	}
 
 	batch := &pgx.Batch{}
 
 	batch.Queue(`
-		SELECT contests.*, users.username AS creator_username, COUNT(entries.id) AS participants
+		SELECT contests.*, users.username AS creator_username, COUNT(entries.id) FILTER (WHERE NOT entries.upsolving) AS participants
 		FROM contests
 		JOIN users ON users.id = contests.creator_id
 		LEFT JOIN entries ON entries.contest_id = contests.id
-		WHERE contests.end_time >= now()
+		WHERE `+filter+seek+`
 		GROUP BY contests.id, users.username
-		ORDER BY contests.id ASC
-		LIMIT $1 OFFSET $2
-	`, limit, offset)
+		`+having+`
+		ORDER BY `+order+`
+		LIMIT `+arg(f.Limit)+` OFFSET `+arg(offset), args...)
 
-	batch.Queue(`SELECT COUNT(*) FROM contests WHERE contests.end_time >= now()`)
+	batch.Queue(`SELECT COUNT(*) FROM contests WHERE `+filter, countArgs...)
 
 	br := p.pool.SendBatch(ctx, batch)
 	defer br.Close()
@@ -153,6 +290,8 @@ func (p *Postgres) ListAll(ctx context.Context, limit int, offset int) (contests
 			&c.ID, &c.CreatorID, &c.Title, &c.Description,
 			&c.StartTime, &c.EndTime, &c.DurationMins,
 			&c.MaxEntries, &c.AllowLateJoin, &c.CreatedAt,
+			&c.ScoringMode, &c.FreezeMins, &c.Unfrozen, &c.MaxTeamSize,
+			&c.IsPrivate, &c.InviteCode, &c.IsRated, &c.MaxRating, &c.RatedAt, &c.Languages, &c.LeaderboardVisibility,
 			&c.CreatorUsername, &c.Participants,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scan failed: %w", err)
@@ -170,11 +309,16 @@ func (p *Postgres) ListAll(ctx context.Context, limit int, offset int) (contests
 	return contests, total, nil
 }
 
+// escapeLike escapes wildcard characters of LIKE patterns
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 func (p *Postgres) GetWithCreatorID(ctx context.Context, creatorID int32, limit, offset int) (contests []models.Contest, total int, err error) {
 	batch := &pgx.Batch{}
 
 	batch.Queue(`
-		SELECT contests.*, users.username AS creator_username, COUNT(entries.id) AS participants
+		SELECT contests.*, users.username AS creator_username, COUNT(entries.id) FILTER (WHERE NOT entries.upsolving) AS participants
 		FROM contests
 		JOIN users ON users.id = contests.creator_id
 		LEFT JOIN entries ON entries.contest_id = contests.id
@@ -207,6 +351,17 @@ func (p *Postgres) GetWithCreatorID(ctx context.Context, creatorID int32, limit,
 			&c.MaxEntries,
 			&c.AllowLateJoin,
 			&c.CreatedAt,
+			&c.ScoringMode,
+			&c.FreezeMins,
+			&c.Unfrozen,
+			&c.MaxTeamSize,
+			&c.IsPrivate,
+			&c.InviteCode,
+			&c.IsRated,
+			&c.MaxRating,
+			&c.RatedAt,
+			&c.Languages,
+			&c.LeaderboardVisibility,
 			&c.CreatorUsername,
 			&c.Participants,
 		); err != nil {
@@ -226,7 +381,7 @@ func (p *Postgres) GetWithCreatorID(ctx context.Context, creatorID int32, limit,
 
 func (p *Postgres) GetEntriesCount(ctx context.Context, contestID int32) (int32, error) {
 	var count int32
-	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM entries WHERE contest_id = $1`, contestID).Scan(&count)
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM entries WHERE contest_id = $1 AND NOT upsolving`, contestID).Scan(&count)
 	return count, err
 }
 
@@ -236,60 +391,121 @@ func (p *Postgres) IsTitleOccupied(ctx context.Context, title string) (bool, err
 	return count > 0, err
 }
 
-func (p *Postgres) GetLeaderboard(ctx context.Context, contestID, limit, offset int) (leaderboard []models.LeaderboardEntry, total int, err error) {
-	batch := &pgx.Batch{}
+func (p *Postgres) Unfreeze(ctx context.Context, contestID int32) error {
+	_, err := p.pool.Exec(ctx, `UPDATE contests SET unfrozen = true WHERE id = $1`, contestID)
+	return err
+}
 
-	batch.Queue(`
-		SELECT u.id AS user_id, u.username, COALESCE(SUM(
-			CASE
-				WHEN p.difficulty = 'easy' THEN 1
-				WHEN p.difficulty = 'mid' THEN 3
-				WHEN p.difficulty = 'hard' THEN 5
-				ELSE 0
-			END
-		), 0) AS points
-		FROM users u
-		JOIN entries e ON u.id = e.user_id
-		JOIN contests c ON e.contest_id = c.id
-		LEFT JOIN (
-			SELECT DISTINCT entry_id, problem_id
-			FROM submissions
-			WHERE verdict = 'ok'
-		) s ON e.id = s.entry_id
-		LEFT JOIN problems p ON s.problem_id = p.id
-		WHERE c.id = $1
-		GROUP BY u.id, u.username
-		ORDER BY points DESC
-		LIMIT $2 OFFSET $3
-	`, contestID, limit, offset)
+func (p *Postgres) RevealProblem(ctx context.Context, contestID int32, charcode string) (bool, error) {
+	tag, err := p.pool.Exec(ctx, `UPDATE contest_problems SET revealed = true WHERE contest_id = $1 AND charcode = $2`, contestID, charcode)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
 
-	batch.Queue(`
-		SELECT COUNT(DISTINCT u.id)
-		FROM users u
-		JOIN entries e ON u.id = e.user_id
-		WHERE e.contest_id = $1
-	`, contestID)
+func (p *Postgres) IsAllowed(ctx context.Context, contestID, userID int32) (bool, error) {
+	var count int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM contest_allowlist WHERE contest_id = $1 AND user_id = $2`, contestID, userID).Scan(&count)
+	return count > 0, err
+}
 
-	br := p.pool.SendBatch(ctx, batch)
-	defer br.Close()
+func (p *Postgres) GetAllowlist(ctx context.Context, contestID int32) ([]models.User, error) {
+	query := `SELECT u.id, u.username
+		FROM contest_allowlist a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.contest_id = $1
+		ORDER BY a.created_at ASC`
 
-	rows, err := br.Query()
+	rows, err := p.pool.Query(ctx, query, contestID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("leaderboard query failed: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
+	var users []models.User
 	for rows.Next() {
-		var entry models.LeaderboardEntry
-		if err := rows.Scan(&entry.UserID, &entry.Username, &entry.Points); err != nil {
-			return nil, 0, err
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.Username); err != nil {
+			return nil, err
 		}
-		leaderboard = append(leaderboard, entry)
+		users = append(users, u)
 	}
 
-	if err := br.QueryRow().Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("total count query failed: %w", err)
+	return users, rows.Err()
+}
+
+func (p *Postgres) AddToAllowlist(ctx context.Context, contestID, userID int32) error {
+	_, err := p.pool.Exec(ctx, `INSERT INTO contest_allowlist (contest_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, contestID, userID)
+	return err
+}
+
+func (p *Postgres) RemoveFromAllowlist(ctx context.Context, contestID, userID int32) (bool, error) {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM contest_allowlist WHERE contest_id = $1 AND user_id = $2`, contestID, userID)
+	if err != nil {
+		return false, err
 	}
+	return tag.RowsAffected() > 0, nil
+}
 
-	return leaderboard, total, nil
+// ReorderProblems sets position of every contest problem to the index of its charcode
+func (p *Postgres) ReorderProblems(ctx context.Context, contestID int32, charcodes []string) error {
+	query := `UPDATE contest_problems cp SET position = o.ord - 1
+		FROM unnest($2::varchar[]) WITH ORDINALITY AS o(charcode, ord)
+		WHERE cp.contest_id = $1 AND cp.charcode = o.charcode`
+
+	_, err := p.pool.Exec(ctx, query, contestID, charcodes)
+	return err
+}
+
+// GetRole returns role of the user in the contest, or empty string if user has no role
+func (p *Postgres) GetRole(ctx context.Context, contestID, userID int32) (string, error) {
+	var role string
+	err := p.pool.QueryRow(ctx, `SELECT role FROM contest_roles WHERE contest_id = $1 AND user_id = $2`, contestID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return role, err
+}
+
+func (p *Postgres) ListRoles(ctx context.Context, contestID int32) ([]models.ContestRole, error) {
+	query := `SELECT r.contest_id, r.user_id, u.username, r.role, r.created_at
+		FROM contest_roles r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.contest_id = $1
+		ORDER BY r.created_at ASC`
+
+	rows, err := p.pool.Query(ctx, query, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []models.ContestRole
+	for rows.Next() {
+		var r models.ContestRole
+		if err := rows.Scan(&r.ContestID, &r.UserID, &r.Username, &r.Role, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		roles = append(roles, r)
+	}
+
+	return roles, rows.Err()
+}
+
+// SetRole grants the role to the user, replacing the previous one
+func (p *Postgres) SetRole(ctx context.Context, contestID, userID int32, role string) error {
+	query := `INSERT INTO contest_roles (contest_id, user_id, role) VALUES ($1, $2, $3)
+		ON CONFLICT (contest_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+
+	_, err := p.pool.Exec(ctx, query, contestID, userID, role)
+	return err
+}
+
+func (p *Postgres) DeleteRole(ctx context.Context, contestID, userID int32) (bool, error) {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM contest_roles WHERE contest_id = $1 AND user_id = $2`, contestID, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
 }
@@ -2,11 +2,18 @@ package entry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/voidcontests/backend/internal/repository/models"
 )
 
+// uniqueViolation is a Postgres error code of unique constraint violation
+const uniqueViolation = "23505"
+
 type Postgres struct {
 	pool *pgxpool.Pool
 }
@@ -15,26 +22,92 @@ func New(pool *pgxpool.Pool) *Postgres {
 	return &Postgres{pool}
 }
 
-func (p *Postgres) Create(ctx context.Context, contestID int32, userID int32) (int, error) {
-	query := `INSERT INTO entries (contest_id, user_id) VALUES ($1, $2) RETURNING id`
+var (
+	ErrAlreadyExists = errors.New("entry already exists")
+	ErrContestFull   = errors.New("max slots limit reached")
+	// ErrHasSubmissions is returned on deletion of the entry, which was already used to submit
+	ErrHasSubmissions = errors.New("entry has submissions")
+)
 
-	var id int
-	err := p.pool.QueryRow(ctx, query, contestID, userID).Scan(&id)
+// Create atomically registers the user in the contest. Member IDs are all users sharing the entry,
+// including the user itself, and none of them could have another entry for the contest. If team ID
+// is not nil, the entry is shared between all team members. Slots limit is not applied to upsolving entries.
+//
+// Returns ErrAlreadyExists if any of the members is already registered, or ErrContestFull if
+// there are no free slots.
+func (p *Postgres) Create(ctx context.Context, contestID int32, userID int32, teamID *int32, memberIDs []int32, upsolving bool) (int32, error) {
+	tx, err := p.pool.Begin(ctx)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// NOTE: contest row lock serializes registrations, withdrawals and admissions of the contest
+	var maxEntries int32
+	err = tx.QueryRow(ctx, `SELECT max_entries FROM contests WHERE id = $1 FOR UPDATE`, contestID).Scan(&maxEntries)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock contest: %w", err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM entries
+		WHERE contest_id = $1 AND (user_id = ANY($2) OR team_id IN (SELECT team_id FROM team_members WHERE user_id = ANY($2))))`,
+		contestID, memberIDs).Scan(&exists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check entries: %w", err)
+	}
+	if exists {
+		return 0, ErrAlreadyExists
+	}
+
+	if !upsolving && maxEntries != 0 {
+		var count int32
+		err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM entries WHERE contest_id = $1 AND NOT upsolving`, contestID).Scan(&count)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count entries: %w", err)
+		}
+		if count >= maxEntries {
+			return 0, ErrContestFull
+		}
 	}
+
+	var id int32
+	err = tx.QueryRow(ctx, `INSERT INTO entries (contest_id, user_id, team_id, upsolving) VALUES ($1, $2, $3, $4) RETURNING id`,
+		contestID, userID, teamID, upsolving).Scan(&id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return 0, ErrAlreadyExists
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
+
 	return id, nil
 }
 
+// Get returns user's own entry for the contest, or the entry of the team user is member of
 func (p *Postgres) Get(ctx context.Context, contestID int32, userID int32) (models.Entry, error) {
-	query := `SELECT id, contest_id, user_id, created_at FROM entries
-	WHERE contest_id = $1 AND user_id = $2`
+	query := `SELECT id, contest_id, user_id, team_id, upsolving, disqualified_at, disqualification_reason, created_at FROM entries
+	WHERE contest_id = $1 AND (user_id = $2 OR team_id IN (SELECT team_id FROM team_members WHERE user_id = $2))
+	ORDER BY id ASC LIMIT 1`
+
+	return scanEntry(p.pool.QueryRow(ctx, query, contestID, userID))
+}
 
+func scanEntry(row pgx.Row) (models.Entry, error) {
 	var entry models.Entry
-	err := p.pool.QueryRow(ctx, query, contestID, userID).Scan(
+	err := row.Scan(
 		&entry.ID,
 		&entry.ContestID,
 		&entry.UserID,
+		&entry.TeamID,
+		&entry.Upsolving,
+		&entry.DisqualifiedAt,
+		&entry.DisqualificationReason,
 		&entry.CreatedAt,
 	)
 	if err != nil {
@@ -42,3 +115,216 @@ func (p *Postgres) Get(ctx context.Context, contestID int32, userID int32) (mode
 	}
 	return entry, nil
 }
+
+// Delete deletes the entry. If admit is true, the freed slot is given to the first user from
+// the contest waitlist, who has no entry yet and is still eligible: rating is below max rating
+// of the contest, and private contest is accessible by allowlist or the current invite code.
+// Users, who are not eligible anymore, are kept in the waitlist. Returns ID of the admitted user, or zero.
+//
+// Returns ErrHasSubmissions if anything was submitted with the entry.
+func (p *Postgres) Delete(ctx context.Context, contestID int32, entryID int32, admit bool) (int32, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// NOTE: contest row lock serializes registrations, withdrawals and admissions of the contest
+	if _, err := tx.Exec(ctx, `SELECT id FROM contests WHERE id = $1 FOR UPDATE`, contestID); err != nil {
+		return 0, fmt.Errorf("failed to lock contest: %w", err)
+	}
+
+	// NOTE: entry row lock waits for submissions being inserted, and blocks new ones
+	if _, err := tx.Exec(ctx, `SELECT id FROM entries WHERE id = $1 FOR UPDATE`, entryID); err != nil {
+		return 0, fmt.Errorf("failed to lock entry: %w", err)
+	}
+
+	var submitted bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM submissions WHERE entry_id = $1)`, entryID).Scan(&submitted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check submissions: %w", err)
+	}
+	if submitted {
+		return 0, ErrHasSubmissions
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM entries WHERE id = $1`, entryID); err != nil {
+		return 0, fmt.Errorf("failed to delete entry: %w", err)
+	}
+
+	var admitted int32
+	for admit {
+		var userID int32
+		err := tx.QueryRow(ctx, `DELETE FROM contest_waitlist
+			WHERE contest_id = $1 AND user_id = (
+				SELECT w.user_id FROM contest_waitlist w
+				JOIN contests c ON c.id = w.contest_id
+				JOIN users u ON u.id = w.user_id
+				WHERE w.contest_id = $1
+				  AND (c.max_rating = 0 OR u.rating < c.max_rating)
+				  AND (NOT c.is_private
+				       OR EXISTS (SELECT 1 FROM contest_allowlist a WHERE a.contest_id = c.id AND a.user_id = w.user_id)
+				       OR (c.invite_code <> '' AND w.invite_code = c.invite_code))
+				ORDER BY w.created_at ASC, w.user_id ASC LIMIT 1
+			)
+			RETURNING user_id`, contestID).Scan(&userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to pop waitlist: %w", err)
+		}
+
+		// NOTE: user could join the contest with a team while waiting
+		var exists bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM entries
+			WHERE contest_id = $1 AND (user_id = $2 OR team_id IN (SELECT team_id FROM team_members WHERE user_id = $2)))`,
+			contestID, userID).Scan(&exists)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check entry: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		_, err = tx.Exec(ctx, `INSERT INTO entries (contest_id, user_id) VALUES ($1, $2)`, contestID, userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert entry: %w", err)
+		}
+
+		admitted = userID
+		break
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return admitted, nil
+}
+
+// JoinWaitlist puts the user into the contest waitlist and returns user's position in it.
+// Invite code, which user joined private contest with, is kept to re-check access on admission.
+func (p *Postgres) JoinWaitlist(ctx context.Context, contestID int32, userID int32, inviteCode string) (int, error) {
+	_, err := p.pool.Exec(ctx, `INSERT INTO contest_waitlist (contest_id, user_id, invite_code) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+		contestID, userID, inviteCode)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `SELECT COUNT(*) FROM contest_waitlist w
+		WHERE w.contest_id = $1 AND w.created_at <= (SELECT created_at FROM contest_waitlist WHERE contest_id = $1 AND user_id = $2)`
+
+	var position int
+	err = p.pool.QueryRow(ctx, query, contestID, userID).Scan(&position)
+	return position, err
+}
+
+// LeaveWaitlist removes the user from the contest waitlist, reporting whether user was waiting
+func (p *Postgres) LeaveWaitlist(ctx context.Context, contestID int32, userID int32) (bool, error) {
+	tag, err := p.pool.Exec(ctx, `DELETE FROM contest_waitlist WHERE contest_id = $1 AND user_id = $2`, contestID, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// IsWaiting reports whether the user is in the contest waitlist
+func (p *Postgres) IsWaiting(ctx context.Context, contestID int32, userID int32) (bool, error) {
+	var waiting bool
+	err := p.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM contest_waitlist WHERE contest_id = $1 AND user_id = $2)`, contestID, userID).Scan(&waiting)
+	return waiting, err
+}
+
+func (p *Postgres) ListParticipants(ctx context.Context, contestID int32) ([]models.Participant, error) {
+	query := `SELECT e.id AS entry_id, u.id AS user_id, u.username, e.team_id, COALESCE(t.name, '') AS team_name
+		FROM entries e
+		JOIN users u ON u.id = e.user_id
+		LEFT JOIN teams t ON t.id = e.team_id
+		WHERE e.contest_id = $1 AND NOT e.upsolving AND e.disqualified_at IS NULL
+		ORDER BY e.id ASC`
+
+	rows, err := p.pool.Query(ctx, query, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []models.Participant
+	for rows.Next() {
+		var participant models.Participant
+		if err := rows.Scan(&participant.EntryID, &participant.UserID, &participant.Username, &participant.TeamID, &participant.TeamName); err != nil {
+			return nil, err
+		}
+		participants = append(participants, participant)
+	}
+
+	return participants, rows.Err()
+}
+
+// Disqualify marks the entry as disqualified with the reason, and records it in the contest audit log.
+// Returns false if the entry is already disqualified.
+func (p *Postgres) Disqualify(ctx context.Context, entry models.Entry, actorID int32, reason string) (bool, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE entries SET disqualified_at = now(), disqualification_reason = $1
+		WHERE id = $2 AND disqualified_at IS NULL`, reason, entry.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to disqualify entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := audit(ctx, tx, entry, actorID, models.AuditDisqualified, reason); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return true, nil
+}
+
+// Reinstate cancels disqualification of the entry, and records it in the contest audit log.
+// Returns false if the entry is not disqualified.
+func (p *Postgres) Reinstate(ctx context.Context, entry models.Entry, actorID int32) (bool, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE entries SET disqualified_at = NULL, disqualification_reason = ''
+		WHERE id = $1 AND disqualified_at IS NOT NULL`, entry.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to reinstate entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := audit(ctx, tx, entry, actorID, models.AuditReinstated, ""); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return true, nil
+}
+
+func audit(ctx context.Context, tx pgx.Tx, entry models.Entry, actorID int32, action, details string) error {
+	_, err := tx.Exec(ctx, `INSERT INTO contest_audit_log (contest_id, actor_id, action, target_id, details) VALUES ($1, $2, $3, $4, $5)`,
+		entry.ContestID, actorID, action, entry.UserID, details)
+	if err != nil {
+		return fmt.Errorf("failed to record audit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package entry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestCreateConcurrent requires migrated database, which is provided by TEST_POSTGRES_DSN
+func TestCreateConcurrent(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("can't connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	const (
+		maxEntries = 5
+		users      = 20
+		// NOTE: every user joins several times, so duplicate registrations race too
+		joinsPerUser = 2
+	)
+
+	suffix := time.Now().UnixNano()
+
+	userIDs := make([]int32, users)
+	for i := range userIDs {
+		err := pool.QueryRow(ctx, `INSERT INTO users (username, password_hash, role_id)
+			VALUES ($1, '', (SELECT id FROM roles WHERE is_default)) RETURNING id`,
+			fmt.Sprintf("race_%d_%d", suffix, i)).Scan(&userIDs[i])
+		if err != nil {
+			t.Fatalf("can't create user: %v", err)
+		}
+	}
+
+	var contestID int32
+	err = pool.QueryRow(ctx, `INSERT INTO contests (creator_id, title, start_time, end_time, duration_mins, max_entries)
+		VALUES ($1, $2, now() + interval '1 day', now() + interval '2 days', 60, $3) RETURNING id`,
+		userIDs[0], fmt.Sprintf("race_%d", suffix), maxEntries).Scan(&contestID)
+	if err != nil {
+		t.Fatalf("can't create contest: %v", err)
+	}
+
+	t.Cleanup(func() {
+		pool.Exec(ctx, `DELETE FROM entries WHERE contest_id = $1`, contestID)
+		pool.Exec(ctx, `DELETE FROM contests WHERE id = $1`, contestID)
+		pool.Exec(ctx, `DELETE FROM users WHERE id = ANY($1)`, userIDs)
+	})
+
+	repo := New(pool)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, users*joinsPerUser)
+	for _, userID := range userIDs {
+		for range joinsPerUser {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repo.Create(ctx, contestID, userID, nil, []int32{userID}, false)
+				errs <- err
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case errors.Is(err, ErrContestFull), errors.Is(err, ErrAlreadyExists):
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if created != maxEntries {
+		t.Errorf("created %d entries, want %d", created, maxEntries)
+	}
+
+	var count int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM entries WHERE contest_id = $1`, contestID).Scan(&count); err != nil {
+		t.Fatalf("can't count entries: %v", err)
+	}
+	if count != maxEntries {
+		t.Errorf("contest has %d entries, want %d", count, maxEntries)
+	}
+}
@@ -0,0 +1,129 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+const defaultLimit = 100
+
+const (
+	// retryDelay is the delay before the first retry of failed event, it's doubled on every next failure
+	retryDelay = 10 * time.Second
+	// maxRetryDelay limits the delay between retries of failed event
+	maxRetryDelay = time.Hour
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+// ListDue returns lifecycle events, which time has come, but which are not fired yet.
+// Contest is finalized after it has ended and all its submissions are judged.
+// Failed events are skipped until their next attempt, so they don't hold back other events.
+func (p *Postgres) ListDue(ctx context.Context) ([]models.LifecycleEvent, error) {
+	query := `SELECT c.id, d.type, d.due_at
+		FROM contests c
+		CROSS JOIN LATERAL (VALUES
+			('contest.started', c.start_time, 0),
+			('contest.frozen', CASE WHEN c.freeze_mins > 0 THEN c.end_time - make_interval(mins => c.freeze_mins) END, 1),
+			('contest.ended', c.end_time, 2),
+			('contest.finalized', CASE WHEN
+				EXISTS (SELECT 1 FROM contest_lifecycle l WHERE l.contest_id = c.id AND l.type = 'contest.ended')
+				AND NOT EXISTS (
+					SELECT 1 FROM submissions s
+					JOIN entries e ON e.id = s.entry_id
+					WHERE e.contest_id = c.id AND NOT s.upsolving AND s.verdict IN ('pending', 'running')
+				) THEN c.end_time END, 3)
+		) AS d(type, due_at, ord)
+		WHERE d.due_at <= now()
+		  AND NOT EXISTS (SELECT 1 FROM contest_lifecycle l WHERE l.contest_id = c.id AND l.type = d.type)
+		  AND NOT EXISTS (SELECT 1 FROM contest_lifecycle_failures f WHERE f.contest_id = c.id AND f.type = d.type AND f.next_attempt_at > now())
+		ORDER BY d.due_at ASC, d.ord ASC, c.id ASC
+		LIMIT $1`
+
+	rows, err := p.pool.Query(ctx, query, defaultLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []models.LifecycleEvent
+	for rows.Next() {
+		var e models.LifecycleEvent
+		if err := rows.Scan(&e.ContestID, &e.Type, &e.DueAt); err != nil {
+			return nil, err
+		}
+		due = append(due, e)
+	}
+
+	return due, rows.Err()
+}
+
+// Fire runs hook and records the event only if hook succeeds, otherwise the failure is recorded and
+// the event is retried with exponential backoff. Concurrent callers wait for the first one,
+// and skip the event if it's recorded.
+// NOTE: callers are serialized by advisory lock instead of row locks, because hooks run on other
+// connections and may lock the contest row themselves, e.g. to apply rating changes.
+func (p *Postgres) Fire(ctx context.Context, contestID int32, typ string, hook func(ctx context.Context) error) (bool, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, contestID, typ); err != nil {
+		return false, fmt.Errorf("lock event: %w", err)
+	}
+
+	var fired bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM contest_lifecycle WHERE contest_id = $1 AND type = $2)`, contestID, typ).Scan(&fired)
+	if err != nil {
+		return false, fmt.Errorf("check event: %w", err)
+	}
+	if fired {
+		return false, nil
+	}
+
+	if err := hook(ctx); err != nil {
+		// NOTE: hook could fail because of timeout, while the failure still should be recorded
+		if ferr := p.recordFailure(context.WithoutCancel(ctx), contestID, typ, err); ferr != nil {
+			return false, fmt.Errorf("%w (record failure: %v)", err, ferr)
+		}
+		return false, err
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO contest_lifecycle (contest_id, type) VALUES ($1, $2)`, contestID, typ); err != nil {
+		return false, fmt.Errorf("record event: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM contest_lifecycle_failures WHERE contest_id = $1 AND type = $2`, contestID, typ); err != nil {
+		return false, fmt.Errorf("clear failures: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return true, nil
+}
+
+// recordFailure counts failed attempt of the event and postpones the next one
+func (p *Postgres) recordFailure(ctx context.Context, contestID int32, typ string, cause error) error {
+	_, err := p.pool.Exec(ctx, `INSERT INTO contest_lifecycle_failures (contest_id, type, attempts, last_error, next_attempt_at)
+		VALUES ($1, $2, 1, $3, now() + $4::interval)
+		ON CONFLICT (contest_id, type) DO UPDATE SET
+			attempts = contest_lifecycle_failures.attempts + 1,
+			last_error = EXCLUDED.last_error,
+			next_attempt_at = now() + LEAST($4::interval * power(2, LEAST(contest_lifecycle_failures.attempts, 16)), $5::interval)`,
+		contestID, typ, cause.Error(), retryDelay, maxRetryDelay)
+	return err
+}
@@ -11,6 +11,11 @@ import (
 	"github.com/voidcontests/backend/internal/repository/models"
 )
 
+const defaultLimit = 20
+
+// problemColumns lists columns of problems table aliased as `p`, in the order of models.Problem scans
+const problemColumns = `p.id, p.kind, p.writer_id, p.title, p.statement, p.difficulty, p.answer, p.time_limit_ms, p.created_at`
+
 type Postgres struct {
 	pool *pgxpool.Pool
 }
@@ -72,7 +77,7 @@ func (p *Postgres) Create(ctx context.Context, kind string, writerID int32, titl
 }
 
 func (p *Postgres) Get(ctx context.Context, contestID int32, charcode string) (*models.Problem, error) {
-	query := `SELECT p.*, cp.charcode, u.username AS writer_username
+	query := `SELECT ` + problemColumns + `, cp.charcode, cp.points, u.username AS writer_username
 		FROM problems p
 		JOIN contest_problems cp ON p.id = cp.problem_id
 		JOIN users u ON u.id = p.writer_id
@@ -84,7 +89,7 @@ func (p *Postgres) Get(ctx context.Context, contestID int32, charcode string) (*
 	err := row.Scan(
 		&problem.ID, &problem.Kind, &problem.WriterID, &problem.Title, &problem.Statement,
 		&problem.Difficulty, &problem.Answer, &problem.TimeLimitMS, &problem.CreatedAt,
-		&problem.Charcode, &problem.WriterUsername,
+		&problem.Charcode, &problem.Points, &problem.WriterUsername,
 	)
 	if err != nil {
 		return nil, err
@@ -135,7 +140,7 @@ func (p *Postgres) GetExampleCases(ctx context.Context, problemID int32) ([]mode
 }
 
 func (p *Postgres) GetAll(ctx context.Context) ([]models.Problem, error) {
-	query := `SELECT problems.*, users.username AS writer_username FROM problems JOIN users ON users.id = problems.writer_id`
+	query := `SELECT ` + problemColumns + `, u.username AS writer_username FROM problems p JOIN users u ON u.id = p.writer_id`
 
 	rows, err := p.pool.Query(ctx, query)
 	if err != nil {
@@ -162,11 +167,11 @@ func (p *Postgres) GetWithWriterID(ctx context.Context, writerID int32, limit, o
 	batch := &pgx.Batch{}
 
 	batch.Queue(`
-		SELECT problems.*, users.username AS writer_username
-		FROM problems
-		JOIN users ON users.id = problems.writer_id
-		WHERE writer_id = $1
-		ORDER BY problems.id ASC
+		SELECT `+problemColumns+`, u.username AS writer_username
+		FROM problems p
+		JOIN users u ON u.id = p.writer_id
+		WHERE p.writer_id = $1
+		ORDER BY p.id ASC
 		LIMIT $2 OFFSET $3
 	`, writerID, limit, offset)
 
@@ -212,3 +217,184 @@ func (p *Postgres) IsTitleOccupied(ctx context.Context, title string) (bool, err
 
 	return count > 0, nil
 }
+
+// Update changes the problem itself and its charcode and points in the contest. Problem content is changed
+// only if `content` is set.
+// NOTE: problem itself could be shared between several contests, e.g. cloned ones, and the library.
+func (p *Postgres) Update(ctx context.Context, contestID int32, problem *models.Problem, content bool) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if content {
+		query := `UPDATE problems SET title = $1, statement = $2, difficulty = $3, answer = $4, time_limit_ms = $5 WHERE id = $6`
+		_, err := tx.Exec(ctx, query, problem.Title, problem.Statement, problem.Difficulty, problem.Answer, problem.TimeLimitMS, problem.ID)
+		if err != nil {
+			return fmt.Errorf("update problem: %w", err)
+		}
+	}
+
+	query := `UPDATE contest_problems SET charcode = $1, points = $2 WHERE contest_id = $3 AND problem_id = $4`
+	if _, err := tx.Exec(ctx, query, problem.Charcode, problem.Points, contestID, problem.ID); err != nil {
+		return fmt.Errorf("update contest problem: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// CountAvailable returns how many of the problems could be used by the user in contests:
+// problems written by the user and public ones
+func (p *Postgres) CountAvailable(ctx context.Context, userID int32, problemIDs []int32) (int, error) {
+	query := `SELECT COUNT(*) FROM problems WHERE id = ANY($1) AND (writer_id = $2 OR is_public)`
+
+	var count int
+	err := p.pool.QueryRow(ctx, query, problemIDs, userID).Scan(&count)
+	return count, err
+}
+
+// LibraryFilter describes listing of the public problem library
+type LibraryFilter struct {
+	// Query is searched in titles and statements
+	Query string
+	// Tags should be all present in listed problems
+	Tags      []string
+	Kind      string
+	MinRating int32
+	MaxRating int32
+	// UserID is used to mark problems solved by the user, zero for anonymous users
+	UserID int32
+	Limit  int
+	Offset int
+}
+
+// libraryColumns extends problemColumns with library fields and solved marker of the user passed as `userArg` placeholder
+func libraryColumns(userArg string) string {
+	return problemColumns + `, p.is_public, p.tags, p.rating, p.published_at, u.username AS writer_username,
+		EXISTS (
+			SELECT 1 FROM submissions s
+			LEFT JOIN entries e ON e.id = s.entry_id
+			WHERE s.problem_id = p.id AND s.verdict = 'ok'
+			  AND (s.user_id = ` + userArg + ` OR e.user_id = ` + userArg + ` OR e.team_id IN (SELECT team_id FROM team_members WHERE user_id = ` + userArg + `))
+		) AS solved`
+}
+
+// ListLibrary returns public problems matching the filter, the most relevant
+// go first if query is provided, otherwise the latest published ones.
+func (p *Postgres) ListLibrary(ctx context.Context, f LibraryFilter) (problems []models.Problem, total int, err error) {
+	if f.Limit < 0 {
+		f.Limit = defaultLimit
+	}
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{`p.is_public`}
+	order := `p.published_at DESC, p.id DESC`
+
+	if f.Query != "" {
+		q := arg(f.Query)
+		where = append(where, `p.search_vector @@ websearch_to_tsquery('english', `+q+`)`)
+		order = `ts_rank(p.search_vector, websearch_to_tsquery('english', ` + q + `)) DESC, p.id DESC`
+	}
+	if len(f.Tags) > 0 {
+		where = append(where, `p.tags @> `+arg(f.Tags)+`::varchar[]`)
+	}
+	if f.Kind != "" {
+		where = append(where, `p.kind = `+arg(f.Kind)+`::problem_kind`)
+	}
+	if f.MinRating > 0 {
+		where = append(where, `p.rating >= `+arg(f.MinRating))
+	}
+	if f.MaxRating > 0 {
+		where = append(where, `p.rating <= `+arg(f.MaxRating))
+	}
+
+	cond := strings.Join(where, " AND ")
+
+	err = p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM problems p WHERE `+cond, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := p.pool.Query(ctx, `SELECT `+libraryColumns(arg(f.UserID))+`
+		FROM problems p
+		JOIN users u ON u.id = p.writer_id
+		WHERE `+cond+`
+		ORDER BY `+order+`
+		LIMIT `+arg(f.Limit)+` OFFSET `+arg(f.Offset), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		problem, err := scanLibraryProblem(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		problems = append(problems, problem)
+	}
+
+	return problems, total, rows.Err()
+}
+
+// GetByID returns the problem with library fields, marking whether it's solved by the user
+func (p *Postgres) GetByID(ctx context.Context, problemID, userID int32) (*models.Problem, error) {
+	query := `SELECT ` + libraryColumns("$1") + `
+		FROM problems p
+		JOIN users u ON u.id = p.writer_id
+		WHERE p.id = $2`
+
+	problem, err := scanLibraryProblem(p.pool.QueryRow(ctx, query, userID, problemID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &problem, nil
+}
+
+func scanLibraryProblem(row pgx.Row) (models.Problem, error) {
+	var p models.Problem
+	err := row.Scan(
+		&p.ID, &p.Kind, &p.WriterID, &p.Title, &p.Statement, &p.Difficulty,
+		&p.Answer, &p.TimeLimitMS, &p.CreatedAt,
+		&p.IsPublic, &p.Tags, &p.Rating, &p.PublishedAt, &p.WriterUsername, &p.Solved,
+	)
+	return p, err
+}
+
+// Publish adds the problem to the public library, or updates its tags and rating if it's already there
+func (p *Postgres) Publish(ctx context.Context, problemID int32, tags []string, rating int32) error {
+	query := `UPDATE problems SET is_public = true, tags = $1, rating = $2, published_at = COALESCE(published_at, now())
+		WHERE id = $3`
+
+	_, err := p.pool.Exec(ctx, query, tags, rating, problemID)
+	return err
+}
+
+func (p *Postgres) Unpublish(ctx context.Context, problemID int32) error {
+	_, err := p.pool.Exec(ctx, `UPDATE problems SET is_public = false, published_at = NULL WHERE id = $1`, problemID)
+	return err
+}
+
+// IsInUnfinishedContest reports whether the problem is used in a contest, which is not finished yet
+func (p *Postgres) IsInUnfinishedContest(ctx context.Context, problemID int32) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM contest_problems cp
+		JOIN contests c ON c.id = cp.contest_id
+		WHERE cp.problem_id = $1 AND c.end_time > now()
+	)`
+
+	var exists bool
+	err := p.pool.QueryRow(ctx, query, problemID).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,113 @@
+package rating
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+// ListContestants returns participants of the contest, who submitted at least once
+func (p *Postgres) ListContestants(ctx context.Context, contestID int32) ([]models.Contestant, error) {
+	query := `SELECT e.id, u.id, u.rating
+		FROM entries e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.contest_id = $1 AND NOT e.upsolving AND e.disqualified_at IS NULL AND EXISTS (SELECT 1 FROM standings s WHERE s.entry_id = e.id)`
+
+	rows, err := p.pool.Query(ctx, query, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contestants []models.Contestant
+	for rows.Next() {
+		var c models.Contestant
+		if err := rows.Scan(&c.EntryID, &c.UserID, &c.Rating); err != nil {
+			return nil, err
+		}
+		contestants = append(contestants, c)
+	}
+
+	return contestants, rows.Err()
+}
+
+// Apply applies rating deltas of contest participants, and marks contest as rated.
+// Contest which is already rated is left untouched.
+func (p *Postgres) Apply(ctx context.Context, contestID int32, ranks map[int32]int32, deltas map[int32]int32) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// NOTE: contest row lock prevents several replicas from rating the same contest twice
+	var ratedAt *time.Time
+	err = tx.QueryRow(ctx, `SELECT rated_at FROM contests WHERE id = $1 FOR UPDATE`, contestID).Scan(&ratedAt)
+	if err != nil {
+		return fmt.Errorf("failed to lock contest: %w", err)
+	}
+	if ratedAt != nil {
+		return nil
+	}
+
+	for userID, delta := range deltas {
+		var rating int32
+		err := tx.QueryRow(ctx, `UPDATE users SET rating = rating + $2 WHERE id = $1 RETURNING rating`, userID, delta).Scan(&rating)
+		if err != nil {
+			return fmt.Errorf("failed to update rating: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `INSERT INTO rating_changes (contest_id, user_id, rank, old_rating, new_rating) VALUES ($1, $2, $3, $4, $5)`,
+			contestID, userID, ranks[userID], rating-delta, rating)
+		if err != nil {
+			return fmt.Errorf("failed to insert rating change: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE contests SET rated_at = now() WHERE id = $1`, contestID); err != nil {
+		return fmt.Errorf("failed to mark contest as rated: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUser returns rating history of the user, from the oldest change
+func (p *Postgres) ListByUser(ctx context.Context, userID int32) ([]models.RatingChange, error) {
+	query := `SELECT rc.contest_id, c.title, c.creator_id, c.is_private, rc.user_id, rc.rank, rc.old_rating, rc.new_rating, rc.created_at
+		FROM rating_changes rc
+		JOIN contests c ON c.id = rc.contest_id
+		WHERE rc.user_id = $1
+		ORDER BY rc.created_at ASC`
+
+	rows, err := p.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.RatingChange
+	for rows.Next() {
+		var rc models.RatingChange
+		if err := rows.Scan(&rc.ContestID, &rc.ContestTitle, &rc.CreatorID, &rc.IsPrivate, &rc.UserID, &rc.Rank, &rc.OldRating, &rc.NewRating, &rc.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, rc)
+	}
+
+	return changes, rows.Err()
+}
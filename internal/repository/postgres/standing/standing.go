@@ -0,0 +1,80 @@
+package standing
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+func (p *Postgres) ListByContest(ctx context.Context, contestID int32) ([]models.Standing, error) {
+	query := `
+		SELECT s.entry_id, s.problem_id, s.attempts, s.accepted_at, s.best_passed, s.pending,
+		       s.frozen_attempts, s.frozen_accepted_at, s.frozen_best_passed, s.frozen_pending
+		FROM standings s
+		JOIN entries e ON e.id = s.entry_id
+		WHERE e.contest_id = $1
+	`
+
+	rows, err := p.pool.Query(ctx, query, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []models.Standing
+	for rows.Next() {
+		var s models.Standing
+		if err := rows.Scan(
+			&s.EntryID,
+			&s.ProblemID,
+			&s.Attempts,
+			&s.AcceptedAt,
+			&s.BestPassed,
+			&s.Pending,
+			&s.FrozenAttempts,
+			&s.FrozenAcceptedAt,
+			&s.FrozenBestPassed,
+			&s.FrozenPending,
+		); err != nil {
+			return nil, err
+		}
+		standings = append(standings, s)
+	}
+
+	return standings, rows.Err()
+}
+
+// Rebuild recomputes standings of the contest from its submissions
+func (p *Postgres) Rebuild(ctx context.Context, contestID int32) error {
+	_, err := p.pool.Exec(ctx, `SELECT rebuild_standings($1)`, contestID)
+	return err
+}
+
+// ListContestIDs returns IDs of all contests, which standings could be rebuilt
+func (p *Postgres) ListContestIDs(ctx context.Context) ([]int32, error) {
+	rows, err := p.pool.Query(ctx, `SELECT id FROM contests ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
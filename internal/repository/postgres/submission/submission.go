@@ -30,19 +30,20 @@ func New(pool *pgxpool.Pool) *Postgres {
 	return &Postgres{pool}
 }
 
-func (p *Postgres) Create(ctx context.Context, entryID, problemID int32, verdict, answer, code, language string, passedTestsCount int32, stderr string) (models.Submission, error) {
+func (p *Postgres) Create(ctx context.Context, entryID, problemID int32, verdict, answer, code, language string, passedTestsCount int32, stderr string, upsolving bool) (models.Submission, error) {
 	query := `
-		INSERT INTO submissions (entry_id, problem_id, verdict, answer, code, language, passed_tests_count, stderr)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, entry_id, problem_id,
+		INSERT INTO submissions (entry_id, problem_id, verdict, answer, code, language, passed_tests_count, stderr, upsolving)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, entry_id, user_id, problem_id,
 		          (SELECT kind FROM problems WHERE id = $2) AS problem_kind,
-		          verdict, answer, code, language, passed_tests_count, stderr, created_at
+		          verdict, answer, code, language, passed_tests_count, stderr, upsolving, created_at
 	`
 
 	var submission models.Submission
-	err := p.pool.QueryRow(ctx, query, entryID, problemID, verdict, answer, code, language, passedTestsCount, stderr).Scan(
+	err := p.pool.QueryRow(ctx, query, entryID, problemID, verdict, answer, code, language, passedTestsCount, stderr, upsolving).Scan(
 		&submission.ID,
 		&submission.EntryID,
+		&submission.UserID,
 		&submission.ProblemID,
 		&submission.ProblemKind,
 		&submission.Verdict,
@@ -51,6 +52,7 @@ func (p *Postgres) Create(ctx context.Context, entryID, problemID int32, verdict
 		&submission.Language,
 		&submission.PassedTestsCount,
 		&submission.Stderr,
+		&submission.Upsolving,
 		&submission.CreatedAt,
 	)
 
@@ -147,19 +149,19 @@ func (p *Postgres) GetProblemStatuses(ctx context.Context, entryID int32) (map[i
 
 func (p *Postgres) GetByID(ctx context.Context, userID, submissionID int32) (models.Submission, error) {
 	query := `
-		SELECT s.id, s.entry_id, s.problem_id, p.kind AS problem_kind, s.verdict,
-		       s.answer, s.code, s.language, s.passed_tests_count, s.stderr, s.created_at
+		SELECT s.id, s.entry_id, s.user_id, s.problem_id, p.kind AS problem_kind, s.verdict,
+		       s.answer, s.code, s.language, s.passed_tests_count, s.stderr, s.upsolving, s.created_at
 		FROM submissions s
 		JOIN problems p ON p.id = s.problem_id
-		JOIN entries e ON s.entry_id = e.id
-		JOIN users u ON e.user_id = u.id
-		WHERE s.id = $1 AND u.id = $2
+		LEFT JOIN entries e ON s.entry_id = e.id
+		WHERE s.id = $1 AND (s.user_id = $2 OR e.user_id = $2 OR e.team_id IN (SELECT team_id FROM team_members WHERE user_id = $2))
 	`
 
 	var s models.Submission
 	err := p.pool.QueryRow(ctx, query, submissionID, userID).Scan(
 		&s.ID,
 		&s.EntryID,
+		&s.UserID,
 		&s.ProblemID,
 		&s.ProblemKind,
 		&s.Verdict,
@@ -168,6 +170,7 @@ func (p *Postgres) GetByID(ctx context.Context, userID, submissionID int32) (mod
 		&s.Language,
 		&s.PassedTestsCount,
 		&s.Stderr,
+		&s.Upsolving,
 		&s.CreatedAt,
 	)
 
@@ -182,8 +185,8 @@ func (p *Postgres) ListByProblem(ctx context.Context, entryID int32, charcode st
 	batch := &pgx.Batch{}
 
 	batch.Queue(`
-		SELECT s.id, s.entry_id, s.problem_id, p.kind AS problem_kind, s.verdict,
-		       s.answer, s.code, s.language, s.passed_tests_count, s.stderr, s.created_at
+		SELECT s.id, s.entry_id, s.user_id, s.problem_id, p.kind AS problem_kind, s.verdict,
+		       s.answer, s.code, s.language, s.passed_tests_count, s.stderr, s.upsolving, s.created_at
 		FROM submissions s
 		JOIN problems p ON p.id = s.problem_id
 		JOIN entries e ON s.entry_id = e.id
@@ -214,6 +217,148 @@ func (p *Postgres) ListByProblem(ctx context.Context, entryID int32, charcode st
 		if err := rows.Scan(
 			&s.ID,
 			&s.EntryID,
+			&s.UserID,
+			&s.ProblemID,
+			&s.ProblemKind,
+			&s.Verdict,
+			&s.Answer,
+			&s.Code,
+			&s.Language,
+			&s.PassedTestsCount,
+			&s.Stderr,
+			&s.Upsolving,
+			&s.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("row scan failed: %w", err)
+		}
+		items = append(items, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	if err := br.QueryRow().Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count query failed: %w", err)
+	}
+
+	return items, total, nil
+}
+
+// RejudgeSubmission resets verdict of the contest submission, so it's judged again
+func (p *Postgres) RejudgeSubmission(ctx context.Context, contestID, submissionID int32) (bool, error) {
+	n, err := p.rejudge(ctx, contestID, "s.id = $2", submissionID)
+	return n > 0, err
+}
+
+// RejudgeProblem resets verdicts of all submissions to the problem in the contest, so they are judged again
+func (p *Postgres) RejudgeProblem(ctx context.Context, contestID, problemID int32) (int64, error) {
+	return p.rejudge(ctx, contestID, "s.problem_id = $2", problemID)
+}
+
+// NOTE: text answers are checked against the current answer right away, while coding
+// submissions are returned to the judge queue. Submissions, which are not judged yet, are skipped.
+func (p *Postgres) rejudge(ctx context.Context, contestID int32, cond string, arg int32) (int64, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM failed_tests ft
+		USING submissions s, entries e
+		WHERE ft.submission_id = s.id AND e.id = s.entry_id AND e.contest_id = $1
+		  AND s.verdict NOT IN ('pending', 'running') AND `+cond, contestID, arg)
+	if err != nil {
+		return 0, fmt.Errorf("delete failed tests: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE submissions s
+		SET verdict = CASE
+		        WHEN p.kind = 'text_answer_problem' AND s.answer = p.answer THEN 'ok'::verdict
+		        WHEN p.kind = 'text_answer_problem' THEN 'wrong_answer'::verdict
+		        ELSE 'pending'::verdict
+		    END,
+		    passed_tests_count = 0, stderr = '', locked_at = NULL
+		FROM problems p, entries e
+		WHERE p.id = s.problem_id AND e.id = s.entry_id AND e.contest_id = $1
+		  AND s.verdict NOT IN ('pending', 'running') AND `+cond, contestID, arg)
+	if err != nil {
+		return 0, fmt.Errorf("reset verdicts: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// CreatePractice creates submission of the user on a problem outside of any contest
+func (p *Postgres) CreatePractice(ctx context.Context, userID, problemID int32, verdict, answer, code, language string) (models.Submission, error) {
+	query := `
+		INSERT INTO submissions (user_id, problem_id, verdict, answer, code, language, stderr)
+		VALUES ($1, $2, $3, $4, $5, $6, '')
+		RETURNING id, entry_id, user_id, problem_id,
+		          (SELECT kind FROM problems WHERE id = $2) AS problem_kind,
+		          verdict, answer, code, language, passed_tests_count, stderr, upsolving, created_at
+	`
+
+	var submission models.Submission
+	err := p.pool.QueryRow(ctx, query, userID, problemID, verdict, answer, code, language).Scan(
+		&submission.ID,
+		&submission.EntryID,
+		&submission.UserID,
+		&submission.ProblemID,
+		&submission.ProblemKind,
+		&submission.Verdict,
+		&submission.Answer,
+		&submission.Code,
+		&submission.Language,
+		&submission.PassedTestsCount,
+		&submission.Stderr,
+		&submission.Upsolving,
+		&submission.CreatedAt,
+	)
+
+	return submission, err
+}
+
+// ListPractice returns practice submissions of the user on the problem, the latest first
+func (p *Postgres) ListPractice(ctx context.Context, userID, problemID int32, limit int, offset int) (items []models.Submission, total int, err error) {
+	if limit < 0 {
+		limit = defaultLimit
+	}
+
+	batch := &pgx.Batch{}
+
+	batch.Queue(`
+		SELECT s.id, s.entry_id, s.user_id, s.problem_id, p.kind AS problem_kind, s.verdict,
+		       s.answer, s.code, s.language, s.passed_tests_count, s.stderr, s.upsolving, s.created_at
+		FROM submissions s
+		JOIN problems p ON p.id = s.problem_id
+		WHERE s.user_id = $1 AND s.problem_id = $2
+		ORDER BY s.created_at DESC LIMIT $3 OFFSET $4
+	`, userID, problemID, limit, offset)
+
+	batch.Queue(`SELECT COUNT(*) FROM submissions WHERE user_id = $1 AND problem_id = $2`, userID, problemID)
+
+	br := p.pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	rows, err := br.Query()
+	if err != nil {
+		return nil, 0, fmt.Errorf("query rows failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s models.Submission
+		if err := rows.Scan(
+			&s.ID,
+			&s.EntryID,
+			&s.UserID,
 			&s.ProblemID,
 			&s.ProblemKind,
 			&s.Verdict,
@@ -222,6 +367,7 @@ func (p *Postgres) ListByProblem(ctx context.Context, entryID int32, charcode st
 			&s.Language,
 			&s.PassedTestsCount,
 			&s.Stderr,
+			&s.Upsolving,
 			&s.CreatedAt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("row scan failed: %w", err)
@@ -238,3 +384,22 @@ func (p *Postgres) ListByProblem(ctx context.Context, entryID int32, charcode st
 
 	return items, total, nil
 }
+
+// GetPracticeProgress returns amounts of library problems solved and tried, but not solved yet, by the user in practice
+func (p *Postgres) GetPracticeProgress(ctx context.Context, userID int32) (solved, tried int, err error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE accepted),
+			COUNT(*) FILTER (WHERE NOT accepted)
+		FROM (
+			SELECT bool_or(s.verdict = 'ok') AS accepted
+			FROM submissions s
+			JOIN problems p ON p.id = s.problem_id
+			WHERE s.user_id = $1 AND p.is_public
+			GROUP BY s.problem_id
+		) AS progress
+	`
+
+	err = p.pool.QueryRow(ctx, query, userID).Scan(&solved, &tried)
+	return solved, tried, err
+}
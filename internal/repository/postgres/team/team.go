@@ -0,0 +1,248 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+var (
+	ErrTeamTooLarge   = errors.New("team would exceed max team size of the contest it's registered for")
+	ErrAlreadyEntered = errors.New("user already has an entry for the contest team is registered for")
+	ErrRatingTooHigh  = errors.New("user rating isn't below max rating of the contest team is registered for")
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+// Create creates a team with its captain as the first member
+func (p *Postgres) Create(ctx context.Context, captainID int32, name string) (int32, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var teamID int32
+	err = tx.QueryRow(ctx, `INSERT INTO teams (name, captain_id) VALUES ($1, $2) RETURNING id`, name, captainID).Scan(&teamID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert team: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO team_members (team_id, user_id) VALUES ($1, $2)`, teamID, captainID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert captain: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit failed: %w", err)
+	}
+
+	return teamID, nil
+}
+
+func (p *Postgres) GetByID(ctx context.Context, teamID int32) (models.Team, error) {
+	var team models.Team
+	query := `SELECT id, name, captain_id, created_at FROM teams WHERE id = $1`
+	err := p.pool.QueryRow(ctx, query, teamID).Scan(&team.ID, &team.Name, &team.CaptainID, &team.CreatedAt)
+	return team, err
+}
+
+func (p *Postgres) IsNameOccupied(ctx context.Context, name string) (bool, error) {
+	var count int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM teams WHERE LOWER(name) = $1`, strings.ToLower(name)).Scan(&count)
+	return count > 0, err
+}
+
+func (p *Postgres) GetMembers(ctx context.Context, teamID int32) ([]models.User, error) {
+	members, err := p.ListMembers(ctx, []int32{teamID})
+	if err != nil {
+		return nil, err
+	}
+	return members[teamID], nil
+}
+
+// ListMembers returns members of all provided teams, grouped by team ID.
+// NOTE: only public fields and rating, which is needed for registration, are selected.
+func (p *Postgres) ListMembers(ctx context.Context, teamIDs []int32) (map[int32][]models.User, error) {
+	query := `SELECT tm.team_id, u.id, u.username, u.rating
+		FROM team_members tm
+		JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = ANY($1)
+		ORDER BY tm.created_at ASC`
+
+	rows, err := p.pool.Query(ctx, query, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make(map[int32][]models.User)
+	for rows.Next() {
+		var teamID int32
+		var user models.User
+		if err := rows.Scan(&teamID, &user.ID, &user.Username, &user.Rating); err != nil {
+			return nil, err
+		}
+		members[teamID] = append(members[teamID], user)
+	}
+
+	return members, rows.Err()
+}
+
+func (p *Postgres) IsMember(ctx context.Context, teamID, userID int32) (bool, error) {
+	var count int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID).Scan(&count)
+	return count > 0, err
+}
+
+func (p *Postgres) CreateInvitation(ctx context.Context, teamID, userID int32) (int32, error) {
+	var id int32
+	query := `INSERT INTO team_invitations (team_id, user_id) VALUES ($1, $2)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET created_at = now()
+		RETURNING id`
+	err := p.pool.QueryRow(ctx, query, teamID, userID).Scan(&id)
+	return id, err
+}
+
+func (p *Postgres) GetInvitation(ctx context.Context, invitationID int32) (models.TeamInvitation, error) {
+	var inv models.TeamInvitation
+	query := `SELECT ti.id, ti.team_id, t.name AS team_name, ti.user_id, ti.created_at
+		FROM team_invitations ti
+		JOIN teams t ON t.id = ti.team_id
+		WHERE ti.id = $1`
+	err := p.pool.QueryRow(ctx, query, invitationID).Scan(&inv.ID, &inv.TeamID, &inv.TeamName, &inv.UserID, &inv.CreatedAt)
+	return inv, err
+}
+
+func (p *Postgres) ListInvitations(ctx context.Context, userID int32) ([]models.TeamInvitation, error) {
+	query := `SELECT ti.id, ti.team_id, t.name AS team_name, ti.user_id, ti.created_at
+		FROM team_invitations ti
+		JOIN teams t ON t.id = ti.team_id
+		WHERE ti.user_id = $1
+		ORDER BY ti.created_at DESC`
+
+	rows, err := p.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []models.TeamInvitation
+	for rows.Next() {
+		var inv models.TeamInvitation
+		if err := rows.Scan(&inv.ID, &inv.TeamID, &inv.TeamName, &inv.UserID, &inv.CreatedAt); err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, inv)
+	}
+
+	return invitations, rows.Err()
+}
+
+// AcceptInvitation adds invited user to the team and removes the invitation.
+//
+// Returns ErrTeamTooLarge if the team is registered for an unfinished contest, which max team size
+// would be exceeded, ErrAlreadyEntered if the user already has an entry for such contest,
+// or ErrRatingTooHigh if the user's rating isn't below max rating of such contest.
+func (p *Postgres) AcceptInvitation(ctx context.Context, inv models.TeamInvitation) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// NOTE: contest rows lock serializes acceptance with registrations for the same contests
+	rows, err := tx.Query(ctx, `SELECT c.id, c.max_team_size, c.max_rating
+		FROM contests c
+		JOIN entries e ON e.contest_id = c.id
+		WHERE e.team_id = $1 AND NOT e.upsolving AND c.end_time > now()
+		ORDER BY c.id
+		FOR UPDATE OF c`, inv.TeamID)
+	if err != nil {
+		return fmt.Errorf("failed to lock contests: %w", err)
+	}
+
+	var contestIDs []int32
+	var maxTeamSize, maxRating int32
+	for rows.Next() {
+		var id, size, rating int32
+		if err := rows.Scan(&id, &size, &rating); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan contest: %w", err)
+		}
+		contestIDs = append(contestIDs, id)
+		if maxTeamSize == 0 || size < maxTeamSize {
+			maxTeamSize = size
+		}
+		if rating != 0 && (maxRating == 0 || rating < maxRating) {
+			maxRating = rating
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to lock contests: %w", err)
+	}
+
+	if len(contestIDs) > 0 {
+		var size int32
+		err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM team_members WHERE team_id = $1`, inv.TeamID).Scan(&size)
+		if err != nil {
+			return fmt.Errorf("failed to count members: %w", err)
+		}
+		if size+1 > maxTeamSize {
+			return ErrTeamTooLarge
+		}
+
+		var entered bool
+		err = tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM entries
+			WHERE contest_id = ANY($1) AND (user_id = $2 OR team_id IN (SELECT team_id FROM team_members WHERE user_id = $2)))`,
+			contestIDs, inv.UserID).Scan(&entered)
+		if err != nil {
+			return fmt.Errorf("failed to check entries: %w", err)
+		}
+		if entered {
+			return ErrAlreadyEntered
+		}
+
+		if maxRating != 0 {
+			var rating int32
+			err = tx.QueryRow(ctx, `SELECT rating FROM users WHERE id = $1`, inv.UserID).Scan(&rating)
+			if err != nil {
+				return fmt.Errorf("failed to get user rating: %w", err)
+			}
+			if rating >= maxRating {
+				return ErrRatingTooHigh
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM team_invitations WHERE id = $1`, inv.ID); err != nil {
+		return fmt.Errorf("failed to delete invitation: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO team_members (team_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, inv.TeamID, inv.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to insert member: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) DeleteInvitation(ctx context.Context, invitationID int32) error {
+	_, err := p.pool.Exec(ctx, `DELETE FROM team_invitations WHERE id = $1`, invitationID)
+	return err
+}
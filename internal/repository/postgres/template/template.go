@@ -0,0 +1,67 @@
+package template
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{pool}
+}
+
+func (p *Postgres) Create(ctx context.Context, t models.ContestTemplate) (int32, error) {
+	query := `INSERT INTO contest_templates (owner_id, name, duration_mins, scoring_mode, allow_late_join, languages)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	var id int32
+	err := p.pool.QueryRow(ctx, query, t.OwnerID, t.Name, t.DurationMins, t.ScoringMode, t.AllowLateJoin, t.Languages).Scan(&id)
+	return id, err
+}
+
+func (p *Postgres) GetByID(ctx context.Context, templateID int32) (models.ContestTemplate, error) {
+	query := `SELECT id, owner_id, name, duration_mins, scoring_mode, allow_late_join, languages, created_at
+		FROM contest_templates WHERE id = $1`
+
+	var t models.ContestTemplate
+	err := p.pool.QueryRow(ctx, query, templateID).Scan(&t.ID, &t.OwnerID, &t.Name, &t.DurationMins, &t.ScoringMode, &t.AllowLateJoin, &t.Languages, &t.CreatedAt)
+	return t, err
+}
+
+func (p *Postgres) ListByOwner(ctx context.Context, ownerID int32) ([]models.ContestTemplate, error) {
+	query := `SELECT id, owner_id, name, duration_mins, scoring_mode, allow_late_join, languages, created_at
+		FROM contest_templates WHERE owner_id = $1 ORDER BY name ASC`
+
+	rows, err := p.pool.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.ContestTemplate
+	for rows.Next() {
+		var t models.ContestTemplate
+		if err := rows.Scan(&t.ID, &t.OwnerID, &t.Name, &t.DurationMins, &t.ScoringMode, &t.AllowLateJoin, &t.Languages, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, rows.Err()
+}
+
+func (p *Postgres) IsNameOccupied(ctx context.Context, ownerID int32, name string) (bool, error) {
+	var count int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM contest_templates WHERE owner_id = $1 AND name = $2`, ownerID, name).Scan(&count)
+	return count > 0, err
+}
+
+func (p *Postgres) Delete(ctx context.Context, templateID int32) error {
+	_, err := p.pool.Exec(ctx, `DELETE FROM contest_templates WHERE id = $1`, templateID)
+	return err
+}
@@ -18,12 +18,13 @@ func New(pool *pgxpool.Pool) *Postgres {
 func (p *Postgres) GetByCredentials(ctx context.Context, username string, passwordHash string) (models.User, error) {
 	var user models.User
 
-	query := `SELECT id, username, password_hash, role_id, created_at FROM users WHERE username = $1 AND password_hash = $2`
+	query := `SELECT id, username, password_hash, role_id, rating, created_at FROM users WHERE username = $1 AND password_hash = $2`
 	err := p.pool.QueryRow(ctx, query, username, passwordHash).Scan(
 		&user.ID,
 		&user.Username,
 		&user.PasswordHash,
 		&user.RoleID,
+		&user.Rating,
 		&user.CreatedAt,
 	)
 	return user, err
@@ -35,7 +36,7 @@ func (p *Postgres) Create(ctx context.Context, username string, passwordHash str
 	query := `
 		INSERT INTO users (username, password_hash, role_id)
 		VALUES ($1, $2, (SELECT id FROM roles WHERE is_default = true LIMIT 1))
-		RETURNING id, username, password_hash, role_id, created_at
+		RETURNING id, username, password_hash, role_id, rating, created_at
 	`
 
 	err := p.pool.QueryRow(ctx, query, username, passwordHash).Scan(
@@ -43,6 +44,7 @@ func (p *Postgres) Create(ctx context.Context, username string, passwordHash str
 		&user.Username,
 		&user.PasswordHash,
 		&user.RoleID,
+		&user.Rating,
 		&user.CreatedAt,
 	)
 	return user, err
@@ -63,12 +65,28 @@ func (p *Postgres) Exists(ctx context.Context, username string) (bool, error) {
 func (p *Postgres) GetByID(ctx context.Context, id int32) (models.User, error) {
 	var user models.User
 
-	query := `SELECT id, username, password_hash, role_id, created_at FROM users WHERE id = $1`
+	query := `SELECT id, username, password_hash, role_id, rating, created_at FROM users WHERE id = $1`
 	err := p.pool.QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.PasswordHash,
 		&user.RoleID,
+		&user.Rating,
+		&user.CreatedAt,
+	)
+	return user, err
+}
+
+func (p *Postgres) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	var user models.User
+
+	query := `SELECT id, username, password_hash, role_id, rating, created_at FROM users WHERE username = $1`
+	err := p.pool.QueryRow(ctx, query, username).Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.RoleID,
+		&user.Rating,
 		&user.CreatedAt,
 	)
 	return user, err
@@ -109,3 +127,31 @@ func (p *Postgres) GetCreatedContestsCount(ctx context.Context, userID int32) (i
 	err := p.pool.QueryRow(ctx, query, userID).Scan(&count)
 	return count, err
 }
+
+// GetCalendarToken returns token of the user's personal calendar feed, or empty string if it's not issued
+func (p *Postgres) GetCalendarToken(ctx context.Context, userID int32) (string, error) {
+	var token string
+	err := p.pool.QueryRow(ctx, `SELECT COALESCE(calendar_token, '') FROM users WHERE id = $1`, userID).Scan(&token)
+	return token, err
+}
+
+// SetCalendarToken replaces token of the user's personal calendar feed, revoking the previous one
+func (p *Postgres) SetCalendarToken(ctx context.Context, userID int32, token string) error {
+	_, err := p.pool.Exec(ctx, `UPDATE users SET calendar_token = $1 WHERE id = $2`, token, userID)
+	return err
+}
+
+func (p *Postgres) GetByCalendarToken(ctx context.Context, token string) (models.User, error) {
+	var user models.User
+
+	query := `SELECT id, username, password_hash, role_id, rating, created_at FROM users WHERE calendar_token = $1`
+	err := p.pool.QueryRow(ctx, query, token).Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.RoleID,
+		&user.Rating,
+		&user.CreatedAt,
+	)
+	return user, err
+}
@@ -2,27 +2,51 @@ package repository
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/voidcontests/backend/internal/repository/postgres/announcement"
+	"github.com/voidcontests/backend/internal/repository/postgres/audit"
+	"github.com/voidcontests/backend/internal/repository/postgres/clarification"
 	"github.com/voidcontests/backend/internal/repository/postgres/contest"
 	"github.com/voidcontests/backend/internal/repository/postgres/entry"
+	"github.com/voidcontests/backend/internal/repository/postgres/lifecycle"
 	"github.com/voidcontests/backend/internal/repository/postgres/problem"
+	"github.com/voidcontests/backend/internal/repository/postgres/rating"
+	"github.com/voidcontests/backend/internal/repository/postgres/standing"
 	"github.com/voidcontests/backend/internal/repository/postgres/submission"
+	"github.com/voidcontests/backend/internal/repository/postgres/team"
+	"github.com/voidcontests/backend/internal/repository/postgres/template"
 	"github.com/voidcontests/backend/internal/repository/postgres/user"
 )
 
 type Repository struct {
-	User       *user.Postgres
-	Contest    *contest.Postgres
-	Problem    *problem.Postgres
-	Entry      *entry.Postgres
-	Submission *submission.Postgres
+	User          *user.Postgres
+	Contest       *contest.Postgres
+	Problem       *problem.Postgres
+	Entry         *entry.Postgres
+	Submission    *submission.Postgres
+	Team          *team.Postgres
+	Clarification *clarification.Postgres
+	Announcement  *announcement.Postgres
+	Standing      *standing.Postgres
+	Rating        *rating.Postgres
+	Template      *template.Postgres
+	Lifecycle     *lifecycle.Postgres
+	Audit         *audit.Postgres
 }
 
 func New(pool *pgxpool.Pool) *Repository {
 	return &Repository{
-		User:       user.New(pool),
-		Contest:    contest.New(pool),
-		Problem:    problem.New(pool),
-		Entry:      entry.New(pool),
-		Submission: submission.New(pool),
+		User:          user.New(pool),
+		Contest:       contest.New(pool),
+		Problem:       problem.New(pool),
+		Entry:         entry.New(pool),
+		Submission:    submission.New(pool),
+		Team:          team.New(pool),
+		Clarification: clarification.New(pool),
+		Announcement:  announcement.New(pool),
+		Standing:      standing.New(pool),
+		Rating:        rating.New(pool),
+		Template:      template.New(pool),
+		Lifecycle:     lifecycle.New(pool),
+		Audit:         audit.New(pool),
 	}
 }
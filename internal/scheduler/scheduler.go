@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/voidcontests/backend/internal/events"
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/repository"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+const (
+	// checkInterval is how often due lifecycle events are looked for
+	checkInterval = 5 * time.Second
+	// fireTimeout limits running all hooks of a single event, so one stuck event doesn't stop the scheduler
+	fireTimeout = time.Minute
+)
+
+// Hook is called once, when the contest reaches the lifecycle event. If hook
+// fails, the event is not recorded, and all its hooks are retried later with backoff.
+type Hook func(ctx context.Context, contest *models.Contest) error
+
+// Scheduler fires contest lifecycle hooks: start, freeze, end and finalization of results.
+// Fired events are persisted, so events missed while the server was down are fired on the next run,
+// and every event is fired only once across all API replicas.
+type Scheduler struct {
+	repo  *repository.Repository
+	hooks map[string][]Hook
+}
+
+func New(repo *repository.Repository) *Scheduler {
+	return &Scheduler{
+		repo:  repo,
+		hooks: make(map[string][]Hook),
+	}
+}
+
+// On registers hooks of the lifecycle event. Hooks should be registered before Run.
+func (s *Scheduler) On(typ string, hooks ...Hook) {
+	s.hooks[typ] = append(s.hooks[typ], hooks...)
+}
+
+// Run periodically fires due lifecycle events until context is canceled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		due, err := s.repo.Lifecycle.ListDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("scheduler: can't get due events", sl.Err(err))
+		}
+
+		for _, e := range due {
+			if err := s.fire(ctx, e); err != nil && ctx.Err() == nil {
+				slog.Error("scheduler: can't fire event", sl.Err(err), slog.Int("contest_id", int(e.ContestID)), slog.String("type", e.Type))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) fire(ctx context.Context, e models.LifecycleEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, fireTimeout)
+	defer cancel()
+
+	contest, err := s.repo.Contest.GetByID(ctx, e.ContestID)
+	if err != nil {
+		return fmt.Errorf("can't get contest: %w", err)
+	}
+
+	fired, err := s.repo.Lifecycle.Fire(ctx, e.ContestID, e.Type, func(ctx context.Context) error {
+		for _, hook := range s.hooks[e.Type] {
+			if err := hook(ctx, contest); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if fired {
+		slog.Info("scheduler: event fired", slog.Int("contest_id", int(e.ContestID)), slog.String("type", e.Type))
+	}
+
+	return nil
+}
+
+// Publish returns hook delivering the lifecycle event to contest subscribers
+func Publish(broker *events.Broker, typ string) Hook {
+	return func(ctx context.Context, contest *models.Contest) error {
+		return broker.Publish(ctx, contest.ID, 0, typ, nil)
+	}
+}
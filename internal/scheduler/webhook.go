@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/voidcontests/backend/internal/lib/logger/sl"
+	"github.com/voidcontests/backend/internal/repository/models"
+)
+
+const webhookTimeout = 5 * time.Second
+
+type webhookPayload struct {
+	Type      string    `json:"type"`
+	ContestID int32     `json:"contest_id"`
+	Title     string    `json:"title"`
+	FiredAt   time.Time `json:"fired_at"`
+}
+
+// Webhook returns hook posting the lifecycle event as JSON to the url.
+// NOTE: delivery is best-effort, failures are only logged and never block other hooks.
+func Webhook(url, typ string) Hook {
+	client := &http.Client{Timeout: webhookTimeout}
+
+	return func(ctx context.Context, contest *models.Contest) error {
+		if err := deliver(ctx, client, url, webhookPayload{
+			Type:      typ,
+			ContestID: contest.ID,
+			Title:     contest.Title,
+			FiredAt:   time.Now(),
+		}); err != nil {
+			slog.Warn("scheduler: webhook delivery failed", sl.Err(err), slog.String("url", url), slog.Int("contest_id", int(contest.ID)))
+		}
+		return nil
+	}
+}
+
+func deliver(ctx context.Context, client *http.Client, url string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("can't marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("can't create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	ModePoints     = "points"
+	ModeICPC       = "icpc"
+	ModeIOI        = "ioi"
+	ModeCodeforces = "codeforces"
+)
+
+type Contest struct {
+	StartTime time.Time
+	EndTime   time.Time
+	Problems  []Problem
+}
+
+type Problem struct {
+	ID         int32
+	Charcode   string
+	Points     int32
+	TestsCount int32
+}
+
+type Participant struct {
+	EntryID  int32
+	UserID   int32
+	Username string
+	// TeamID is zero for individual participants
+	TeamID   int32
+	TeamName string
+}
+
+// Key identifies participant's results on a single problem
+type Key struct {
+	EntryID   int32
+	ProblemID int32
+}
+
+// Result is an aggregated outcome of participant's submissions on a single problem.
+// Results are aggregated by the database, see `standings` table.
+type Result struct {
+	// Attempts is an amount of judged attempts up to and including the first accepted one
+	Attempts   int32
+	Accepted   bool
+	AcceptedAt time.Time
+	BestPassed int32
+	// Pending is an amount of attempts which results are not known (or hidden) yet
+	Pending int32
+}
+
+type Row struct {
+	Rank int
+	Participant
+	Points  float64
+	Solved  int
+	Penalty int
+	Pending int
+	// Cells are results on every contest problem, in the order of contest problems
+	Cells []Cell
+}
+
+// Cell is a participant's result on a single problem
+type Cell struct {
+	Charcode string
+	Points   float64
+	Attempts int32
+	Accepted bool
+	// AcceptedMins is an amount of minutes since the contest start until the first accepted attempt
+	AcceptedMins int
+	Pending      int32
+	// FirstToSolve marks the earliest accepted attempts on the problem among all participants
+	FirstToSolve bool
+}
+
+// Scorer describes rules of a single scoring mode
+type Scorer interface {
+	// Score returns amount of points the result is worth
+	Score(c Contest, p Problem, r Result) float64
+	// Less reports whether row `a` should be ranked strictly higher than row `b`
+	Less(a, b Row) bool
+}
+
+// New returns scorer for provided scoring mode
+func New(mode string) (Scorer, error) {
+	switch mode {
+	case ModePoints:
+		return Points{}, nil
+	case ModeICPC:
+		return ICPC{}, nil
+	case ModeIOI:
+		return IOI{}, nil
+	case ModeCodeforces:
+		return Codeforces{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown scoring mode: %s", mode)
+}
+
+// Standings computes ranked leaderboard rows for all participants
+func Standings(s Scorer, c Contest, participants []Participant, results map[Key]Result) []Row {
+	rows := make([]Row, len(participants))
+	for i, participant := range participants {
+		row := Row{Participant: participant, Cells: make([]Cell, len(c.Problems))}
+		for j, p := range c.Problems {
+			row.Cells[j].Charcode = p.Charcode
+
+			r, ok := results[Key{EntryID: participant.EntryID, ProblemID: p.ID}]
+			if !ok {
+				continue
+			}
+
+			score := s.Score(c, p, r)
+			cell := Cell{
+				Charcode: p.Charcode,
+				Points:   round(score),
+				Attempts: r.Attempts,
+				Accepted: r.Accepted,
+				Pending:  r.Pending,
+			}
+
+			row.Points += score
+			row.Pending += int(r.Pending)
+			if r.Accepted {
+				cell.AcceptedMins = minutesSince(c.StartTime, r.AcceptedAt)
+				row.Solved++
+				row.Penalty += cell.AcceptedMins + 20*int(r.Attempts-1)
+			}
+			row.Cells[j] = cell
+		}
+		row.Points = round(row.Points)
+		rows[i] = row
+	}
+
+	for j, p := range c.Problems {
+		var first time.Time
+		for _, participant := range participants {
+			r := results[Key{EntryID: participant.EntryID, ProblemID: p.ID}]
+			if r.Accepted && (first.IsZero() || r.AcceptedAt.Before(first)) {
+				first = r.AcceptedAt
+			}
+		}
+		if first.IsZero() {
+			continue
+		}
+
+		for i, participant := range participants {
+			r := results[Key{EntryID: participant.EntryID, ProblemID: p.ID}]
+			rows[i].Cells[j].FirstToSolve = r.Accepted && r.AcceptedAt.Equal(first)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if s.Less(rows[i], rows[j]) {
+			return true
+		}
+		if s.Less(rows[j], rows[i]) {
+			return false
+		}
+		return rows[i].Username < rows[j].Username
+	})
+
+	for i := range rows {
+		if i > 0 && !s.Less(rows[i-1], rows[i]) {
+			rows[i].Rank = rows[i-1].Rank
+		} else {
+			rows[i].Rank = i + 1
+		}
+	}
+
+	return rows
+}
+
+// Points gives full problem points for every accepted problem
+type Points struct{}
+
+func (Points) Score(_ Contest, p Problem, r Result) float64 {
+	if r.Accepted {
+		return float64(p.Points)
+	}
+	return 0
+}
+
+func (Points) Less(a, b Row) bool {
+	return a.Points > b.Points
+}
+
+// ICPC ranks by amount of solved problems, then by penalty time
+type ICPC struct{}
+
+func (ICPC) Score(_ Contest, _ Problem, r Result) float64 {
+	if r.Accepted {
+		return 1
+	}
+	return 0
+}
+
+func (ICPC) Less(a, b Row) bool {
+	if a.Solved != b.Solved {
+		return a.Solved > b.Solved
+	}
+	return a.Penalty < b.Penalty
+}
+
+// IOI gives partial points proportional to the best amount of passed tests
+type IOI struct{}
+
+func (IOI) Score(_ Contest, p Problem, r Result) float64 {
+	if r.Accepted {
+		return float64(p.Points)
+	}
+	if p.TestsCount == 0 {
+		return 0
+	}
+	return float64(p.Points) * float64(r.BestPassed) / float64(p.TestsCount)
+}
+
+func (IOI) Less(a, b Row) bool {
+	return a.Points > b.Points
+}
+
+// Codeforces decays problem points with time and wrong attempts, but never below 30% of it
+type Codeforces struct{}
+
+func (Codeforces) Score(c Contest, p Problem, r Result) float64 {
+	if !r.Accepted {
+		return 0
+	}
+
+	x := float64(p.Points)
+	minutes := float64(minutesSince(c.StartTime, r.AcceptedAt))
+	wrong := float64(r.Attempts - 1)
+
+	return math.Max(0.3*x, x-x*minutes/250-x*wrong/10)
+}
+
+func (Codeforces) Less(a, b Row) bool {
+	return a.Points > b.Points
+}
+
+func minutesSince(start, t time.Time) int {
+	if t.Before(start) {
+		return 0
+	}
+	return int(t.Sub(start).Minutes())
+}
+
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
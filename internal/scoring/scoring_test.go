@@ -0,0 +1,133 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+func at(mins int) time.Time {
+	return start.Add(time.Duration(mins) * time.Minute)
+}
+
+func TestScore(t *testing.T) {
+	contest := Contest{StartTime: start, EndTime: at(120)}
+
+	tests := []struct {
+		name   string
+		scorer Scorer
+		p      Problem
+		r      Result
+		want   float64
+	}{
+		{"points accepted", Points{}, Problem{Points: 3}, Result{Attempts: 2, Accepted: true, AcceptedAt: at(10)}, 3},
+		{"points rejected", Points{}, Problem{Points: 3}, Result{Attempts: 2, BestPassed: 5}, 0},
+		{"icpc accepted", ICPC{}, Problem{Points: 3}, Result{Attempts: 1, Accepted: true, AcceptedAt: at(10)}, 1},
+		{"icpc rejected", ICPC{}, Problem{Points: 3}, Result{Attempts: 3}, 0},
+		{"ioi accepted", IOI{}, Problem{Points: 100, TestsCount: 10}, Result{Attempts: 1, Accepted: true, AcceptedAt: at(10)}, 100},
+		{"ioi partial", IOI{}, Problem{Points: 100, TestsCount: 10}, Result{Attempts: 1, BestPassed: 7}, 70},
+		{"ioi no tests", IOI{}, Problem{Points: 100}, Result{Attempts: 1, BestPassed: 7}, 0},
+		{"codeforces immediately", Codeforces{}, Problem{Points: 500}, Result{Attempts: 1, Accepted: true, AcceptedAt: start}, 500},
+		{"codeforces decayed", Codeforces{}, Problem{Points: 500}, Result{Attempts: 2, Accepted: true, AcceptedAt: at(50)}, 350},
+		{"codeforces floor", Codeforces{}, Problem{Points: 500}, Result{Attempts: 10, Accepted: true, AcceptedAt: at(200)}, 150},
+		{"codeforces rejected", Codeforces{}, Problem{Points: 500}, Result{Attempts: 1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scorer.Score(contest, tt.p, tt.r); got != tt.want {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStandingsICPCPenalty(t *testing.T) {
+	contest := Contest{StartTime: start, EndTime: at(120), Problems: []Problem{{ID: 1, Charcode: "A", Points: 1}}}
+	participants := []Participant{{EntryID: 1, Username: "alice"}, {EntryID: 2, Username: "bob"}}
+	results := map[Key]Result{
+		// 30 + 2 wrong attempts * 20 = 70
+		{EntryID: 1, ProblemID: 1}: {Attempts: 3, Accepted: true, AcceptedAt: at(30)},
+		{EntryID: 2, ProblemID: 1}: {Attempts: 1, Accepted: true, AcceptedAt: at(60)},
+	}
+
+	rows := Standings(ICPC{}, contest, participants, results)
+
+	want := []struct {
+		username string
+		rank     int
+		penalty  int
+	}{
+		{"bob", 1, 60},
+		{"alice", 2, 70},
+	}
+	for i, w := range want {
+		if rows[i].Username != w.username || rows[i].Rank != w.rank || rows[i].Penalty != w.penalty {
+			t.Errorf("row %d = %s (rank %d, penalty %d), want %s (rank %d, penalty %d)",
+				i, rows[i].Username, rows[i].Rank, rows[i].Penalty, w.username, w.rank, w.penalty)
+		}
+	}
+}
+
+func TestStandingsTies(t *testing.T) {
+	contest := Contest{StartTime: start, EndTime: at(120), Problems: []Problem{
+		{ID: 1, Charcode: "A", Points: 1},
+		{ID: 2, Charcode: "B", Points: 1},
+	}}
+	participants := []Participant{
+		{EntryID: 1, Username: "dave"},
+		{EntryID: 2, Username: "carol"},
+		{EntryID: 3, Username: "bob"},
+		{EntryID: 4, Username: "alice"},
+	}
+	results := map[Key]Result{
+		{EntryID: 1, ProblemID: 1}: {Attempts: 1, Accepted: true, AcceptedAt: at(10)},
+		{EntryID: 1, ProblemID: 2}: {Attempts: 1, Accepted: true, AcceptedAt: at(20)},
+		{EntryID: 2, ProblemID: 1}: {Attempts: 1, Accepted: true, AcceptedAt: at(10)},
+		{EntryID: 3, ProblemID: 1}: {Attempts: 1, Accepted: true, AcceptedAt: at(15)},
+	}
+
+	rows := Standings(Points{}, contest, participants, results)
+
+	want := []struct {
+		username string
+		rank     int
+	}{
+		{"dave", 1},
+		// NOTE: tied participants share the rank and are ordered by username
+		{"bob", 2},
+		{"carol", 2},
+		{"alice", 4},
+	}
+	for i, w := range want {
+		if rows[i].Username != w.username || rows[i].Rank != w.rank {
+			t.Errorf("row %d = %s (rank %d), want %s (rank %d)", i, rows[i].Username, rows[i].Rank, w.username, w.rank)
+		}
+	}
+}
+
+func TestStandingsFirstToSolve(t *testing.T) {
+	contest := Contest{StartTime: start, EndTime: at(120), Problems: []Problem{{ID: 1, Charcode: "A", Points: 1}}}
+	participants := []Participant{
+		{EntryID: 1, Username: "alice"},
+		{EntryID: 2, Username: "bob"},
+		{EntryID: 3, Username: "carol"},
+		{EntryID: 4, Username: "dave"},
+	}
+	results := map[Key]Result{
+		{EntryID: 1, ProblemID: 1}: {Attempts: 1, Accepted: true, AcceptedAt: at(10)},
+		{EntryID: 2, ProblemID: 1}: {Attempts: 2, Accepted: true, AcceptedAt: at(10)},
+		{EntryID: 3, ProblemID: 1}: {Attempts: 1, Accepted: true, AcceptedAt: at(11)},
+		{EntryID: 4, ProblemID: 1}: {Attempts: 1},
+	}
+
+	rows := Standings(ICPC{}, contest, participants, results)
+
+	want := map[string]bool{"alice": true, "bob": true, "carol": false, "dave": false}
+	for _, row := range rows {
+		if got := row.Cells[0].FirstToSolve; got != want[row.Username] {
+			t.Errorf("%s: FirstToSolve = %v, want %v", row.Username, got, want[row.Username])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package standings
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/voidcontests/backend/internal/repository"
+	"github.com/voidcontests/backend/internal/repository/models"
+	"github.com/voidcontests/backend/internal/scoring"
+)
+
+// Compute computes full ranked leaderboard of the contest with its scoring mode.
+// If frozen, results submitted after the freeze are hidden as pending, except
+// the results of not frozen problems and the results of the viewer's entry, zero if none.
+func Compute(ctx context.Context, repo *repository.Repository, contest *models.Contest, frozen bool, viewerEntryID int32) ([]scoring.Row, error) {
+	scorer, err := scoring.New(contest.ScoringMode)
+	if err != nil {
+		return nil, err
+	}
+
+	problems, err := repo.Contest.GetProblemset(ctx, contest.ID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get problemset: %v", err)
+	}
+
+	ps, err := repo.Entry.ListParticipants(ctx, contest.ID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get participants: %v", err)
+	}
+
+	ss, err := repo.Standing.ListByContest(ctx, contest.ID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get standings: %v", err)
+	}
+
+	sc := scoring.Contest{
+		StartTime: contest.StartTime,
+		EndTime:   contest.EndTime,
+		Problems:  make([]scoring.Problem, len(problems)),
+	}
+	for i, p := range problems {
+		sc.Problems[i] = scoring.Problem{
+			ID:         p.ID,
+			Charcode:   p.Charcode,
+			Points:     p.Points,
+			TestsCount: p.TestsCount,
+		}
+	}
+
+	participants := make([]scoring.Participant, len(ps))
+	for i, p := range ps {
+		participants[i] = scoring.Participant{
+			EntryID:  p.EntryID,
+			UserID:   p.UserID,
+			Username: p.Username,
+			TeamName: p.TeamName,
+		}
+		if p.TeamID != nil {
+			participants[i].TeamID = *p.TeamID
+		}
+	}
+
+	revealed := make(map[int32]bool)
+	for _, p := range problems {
+		revealed[p.ID] = p.Revealed
+	}
+
+	results := make(map[scoring.Key]scoring.Result, len(ss))
+	for _, s := range ss {
+		r := scoring.Result{
+			Attempts:   s.Attempts,
+			BestPassed: s.BestPassed,
+			Pending:    s.Pending,
+		}
+		if s.AcceptedAt != nil {
+			r.Accepted = true
+			r.AcceptedAt = *s.AcceptedAt
+		}
+
+		if frozen && !revealed[s.ProblemID] && (viewerEntryID == 0 || s.EntryID != viewerEntryID) {
+			r = scoring.Result{
+				Attempts:   s.FrozenAttempts,
+				BestPassed: s.FrozenBestPassed,
+				Pending:    s.FrozenPending,
+			}
+			if s.FrozenAcceptedAt != nil {
+				r.Accepted = true
+				r.AcceptedAt = *s.FrozenAcceptedAt
+			}
+		}
+
+		results[scoring.Key{EntryID: s.EntryID, ProblemID: s.ProblemID}] = r
+	}
+
+	return scoring.Standings(scorer, sc, participants, results), nil
+}
@@ -0,0 +1 @@
+ALTER TABLE contest_problems DROP COLUMN IF EXISTS points;
@@ -0,0 +1,11 @@
+ALTER TABLE contest_problems ADD COLUMN points INTEGER DEFAULT 1 NOT NULL;
+
+UPDATE contest_problems cp
+SET points = CASE
+    WHEN p.difficulty = 'easy' THEN 1
+    WHEN p.difficulty = 'mid' THEN 3
+    WHEN p.difficulty = 'hard' THEN 5
+    ELSE 1
+END
+FROM problems p
+WHERE p.id = cp.problem_id;
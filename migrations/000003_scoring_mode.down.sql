@@ -0,0 +1,3 @@
+ALTER TABLE contests DROP COLUMN IF EXISTS scoring_mode;
+
+DROP TYPE IF EXISTS scoring_mode;
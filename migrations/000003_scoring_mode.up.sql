@@ -0,0 +1,3 @@
+CREATE TYPE scoring_mode AS ENUM ('points', 'icpc', 'ioi', 'codeforces');
+
+ALTER TABLE contests ADD COLUMN scoring_mode scoring_mode DEFAULT 'points' NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE contest_problems DROP COLUMN IF EXISTS revealed;
+
+ALTER TABLE contests DROP COLUMN IF EXISTS unfrozen;
+ALTER TABLE contests DROP COLUMN IF EXISTS freeze_mins;
@@ -0,0 +1,4 @@
+ALTER TABLE contests ADD COLUMN freeze_mins INTEGER DEFAULT 0 NOT NULL; -- 0 - never frozen
+ALTER TABLE contests ADD COLUMN unfrozen BOOLEAN DEFAULT false NOT NULL;
+
+ALTER TABLE contest_problems ADD COLUMN revealed BOOLEAN DEFAULT false NOT NULL;
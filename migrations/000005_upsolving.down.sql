@@ -0,0 +1,3 @@
+ALTER TABLE submissions DROP COLUMN IF EXISTS upsolving;
+
+ALTER TABLE entries DROP COLUMN IF EXISTS upsolving;
@@ -0,0 +1,4 @@
+-- NOTE: upsolving entries are created after the contest end and never appear in the leaderboard
+ALTER TABLE entries ADD COLUMN upsolving BOOLEAN DEFAULT false NOT NULL;
+
+ALTER TABLE submissions ADD COLUMN upsolving BOOLEAN DEFAULT false NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE entries DROP COLUMN IF EXISTS team_id;
+
+ALTER TABLE contests DROP COLUMN IF EXISTS max_team_size;
+
+DROP TABLE IF EXISTS team_invitations;
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
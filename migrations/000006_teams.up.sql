@@ -0,0 +1,28 @@
+CREATE TABLE teams
+(
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    captain_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE TABLE team_members
+(
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE TABLE team_invitations
+(
+    id SERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    UNIQUE (team_id, user_id)
+);
+
+ALTER TABLE contests ADD COLUMN max_team_size INTEGER DEFAULT 1 NOT NULL; -- 1 - individual participation only
+
+ALTER TABLE entries ADD COLUMN team_id INTEGER REFERENCES teams(id);
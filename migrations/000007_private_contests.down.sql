@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS contest_allowlist;
+
+ALTER TABLE contests DROP COLUMN IF EXISTS invite_code;
+ALTER TABLE contests DROP COLUMN IF EXISTS is_private;
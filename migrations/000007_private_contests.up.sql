@@ -0,0 +1,10 @@
+ALTER TABLE contests ADD COLUMN is_private BOOLEAN DEFAULT false NOT NULL;
+ALTER TABLE contests ADD COLUMN invite_code VARCHAR(64) DEFAULT '' NOT NULL; -- '' - joinable only by allowlist
+
+CREATE TABLE contest_allowlist
+(
+    contest_id INTEGER NOT NULL REFERENCES contests(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
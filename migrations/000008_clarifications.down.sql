@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS clarification_reads;
+DROP TABLE IF EXISTS clarifications;
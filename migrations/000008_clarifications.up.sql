@@ -0,0 +1,22 @@
+CREATE TABLE clarifications
+(
+    id SERIAL PRIMARY KEY,
+    contest_id INTEGER NOT NULL REFERENCES contests(id),
+    problem_id INTEGER REFERENCES problems(id), -- NULL - general question about the contest
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    question TEXT NOT NULL,
+    answer TEXT DEFAULT '' NOT NULL,
+    is_public BOOLEAN DEFAULT false NOT NULL,
+    answered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX clarifications_contest_id_idx ON clarifications(contest_id);
+
+CREATE TABLE clarification_reads
+(
+    contest_id INTEGER NOT NULL REFERENCES contests(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    read_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
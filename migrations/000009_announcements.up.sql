@@ -0,0 +1,11 @@
+CREATE TABLE announcements
+(
+    id SERIAL PRIMARY KEY,
+    contest_id INTEGER NOT NULL REFERENCES contests(id),
+    author_id INTEGER NOT NULL REFERENCES users(id),
+    text VARCHAR(1000) NOT NULL,
+    updated_at TIMESTAMP DEFAULT now() NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX announcements_contest_id_idx ON announcements(contest_id);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS submissions_leaderboard_updated ON submissions;
+
+DROP FUNCTION IF EXISTS notify_leaderboard_updated();
@@ -0,0 +1,18 @@
+-- NOTE: verdicts are written by the judge directly into the database, so leaderboard
+-- updates are announced by trigger, in the same format as application events
+CREATE FUNCTION notify_leaderboard_updated() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('contest_events', json_build_object(
+        'type', 'leaderboard.updated',
+        'contest_id', (SELECT contest_id FROM entries WHERE id = NEW.entry_id),
+        'created_at', now()
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER submissions_leaderboard_updated
+    AFTER INSERT OR UPDATE OF verdict ON submissions
+    FOR EACH ROW
+    WHEN (NOT NEW.upsolving)
+    EXECUTE FUNCTION notify_leaderboard_updated();
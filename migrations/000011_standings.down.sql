@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS submissions_refresh_standing ON submissions;
+
+DROP FUNCTION IF EXISTS submissions_refresh_standing();
+DROP FUNCTION IF EXISTS rebuild_standings(INTEGER);
+DROP FUNCTION IF EXISTS refresh_standing(INTEGER, INTEGER);
+
+DROP TABLE IF EXISTS standings;
@@ -0,0 +1,115 @@
+-- NOTE: every row keeps two versions of the result: the real one, and the one
+-- visible while the leaderboard is frozen, where submissions after the freeze are pending
+CREATE TABLE standings
+(
+    entry_id INTEGER NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+    problem_id INTEGER NOT NULL REFERENCES problems(id),
+    attempts INTEGER DEFAULT 0 NOT NULL,
+    accepted_at TIMESTAMP,
+    best_passed INTEGER DEFAULT 0 NOT NULL,
+    pending INTEGER DEFAULT 0 NOT NULL,
+    frozen_attempts INTEGER DEFAULT 0 NOT NULL,
+    frozen_accepted_at TIMESTAMP,
+    frozen_best_passed INTEGER DEFAULT 0 NOT NULL,
+    frozen_pending INTEGER DEFAULT 0 NOT NULL,
+    PRIMARY KEY (entry_id, problem_id)
+);
+
+-- refresh_standing recomputes result of a single entry on a single problem from its submissions.
+-- Not judged submissions and compilation errors are never counted as attempts, and
+-- everything after the first accepted submission is ignored.
+CREATE FUNCTION refresh_standing(p_entry_id INTEGER, p_problem_id INTEGER) RETURNS void AS $$
+DECLARE
+    freeze_time TIMESTAMP;
+    s RECORD;
+    r standings%ROWTYPE;
+BEGIN
+    -- NOTE: row lock serializes concurrent verdicts of the same entry and problem,
+    -- so the last transaction always sees submissions committed by the previous ones
+    INSERT INTO standings (entry_id, problem_id) VALUES (p_entry_id, p_problem_id) ON CONFLICT DO NOTHING;
+    PERFORM 1 FROM standings WHERE entry_id = p_entry_id AND problem_id = p_problem_id FOR UPDATE;
+
+    SELECT c.end_time - make_interval(mins => c.freeze_mins) INTO freeze_time
+    FROM entries e
+    JOIN contests c ON c.id = e.contest_id
+    WHERE e.id = p_entry_id;
+
+    r.attempts := 0; r.best_passed := 0; r.pending := 0;
+    r.frozen_attempts := 0; r.frozen_best_passed := 0; r.frozen_pending := 0;
+
+    FOR s IN
+        SELECT verdict, passed_tests_count, created_at
+        FROM submissions
+        WHERE entry_id = p_entry_id AND problem_id = p_problem_id AND NOT upsolving
+        ORDER BY created_at ASC, id ASC
+    LOOP
+        IF r.accepted_at IS NULL THEN
+            IF s.verdict IN ('pending', 'running') THEN
+                r.pending := r.pending + 1;
+            ELSIF s.verdict <> 'compilation_error' THEN
+                r.attempts := r.attempts + 1;
+                r.best_passed := GREATEST(r.best_passed, s.passed_tests_count);
+                IF s.verdict = 'ok' THEN
+                    r.accepted_at := s.created_at;
+                END IF;
+            END IF;
+        END IF;
+
+        IF r.frozen_accepted_at IS NULL THEN
+            IF s.created_at >= freeze_time OR s.verdict IN ('pending', 'running') THEN
+                r.frozen_pending := r.frozen_pending + 1;
+            ELSIF s.verdict <> 'compilation_error' THEN
+                r.frozen_attempts := r.frozen_attempts + 1;
+                r.frozen_best_passed := GREATEST(r.frozen_best_passed, s.passed_tests_count);
+                IF s.verdict = 'ok' THEN
+                    r.frozen_accepted_at := s.created_at;
+                END IF;
+            END IF;
+        END IF;
+    END LOOP;
+
+    UPDATE standings SET
+        attempts = r.attempts,
+        accepted_at = r.accepted_at,
+        best_passed = r.best_passed,
+        pending = r.pending,
+        frozen_attempts = r.frozen_attempts,
+        frozen_accepted_at = r.frozen_accepted_at,
+        frozen_best_passed = r.frozen_best_passed,
+        frozen_pending = r.frozen_pending
+    WHERE entry_id = p_entry_id AND problem_id = p_problem_id;
+END;
+$$ LANGUAGE plpgsql;
+
+-- rebuild_standings recomputes all results of the contest from scratch
+CREATE FUNCTION rebuild_standings(p_contest_id INTEGER) RETURNS void AS $$
+DECLARE
+    k RECORD;
+BEGIN
+    DELETE FROM standings WHERE entry_id IN (SELECT id FROM entries WHERE contest_id = p_contest_id);
+
+    FOR k IN
+        SELECT DISTINCT s.entry_id, s.problem_id
+        FROM submissions s
+        JOIN entries e ON e.id = s.entry_id
+        WHERE e.contest_id = p_contest_id AND NOT s.upsolving
+    LOOP
+        PERFORM refresh_standing(k.entry_id, k.problem_id);
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION submissions_refresh_standing() RETURNS trigger AS $$
+BEGIN
+    PERFORM refresh_standing(NEW.entry_id, NEW.problem_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER submissions_refresh_standing
+    AFTER INSERT OR UPDATE OF verdict, passed_tests_count ON submissions
+    FOR EACH ROW
+    WHEN (NOT NEW.upsolving)
+    EXECUTE FUNCTION submissions_refresh_standing();
+
+SELECT rebuild_standings(id) FROM contests;
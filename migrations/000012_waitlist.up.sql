@@ -0,0 +1,7 @@
+CREATE TABLE contest_waitlist
+(
+    contest_id INTEGER NOT NULL REFERENCES contests(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
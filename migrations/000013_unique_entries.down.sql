@@ -0,0 +1 @@
+ALTER TABLE entries DROP CONSTRAINT IF EXISTS entries_contest_id_user_id_key;
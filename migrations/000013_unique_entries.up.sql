@@ -0,0 +1,18 @@
+-- NOTE: duplicate entries could be created by concurrent registrations before this
+-- migration, so their submissions are moved to the earliest entry of the user
+CREATE TEMPORARY TABLE duplicate_entries AS
+SELECT e.id, e.contest_id, first.id AS first_id
+FROM entries e
+JOIN LATERAL (
+    SELECT id FROM entries WHERE contest_id = e.contest_id AND user_id = e.user_id ORDER BY id ASC LIMIT 1
+) first ON first.id <> e.id;
+
+UPDATE submissions s SET entry_id = d.first_id FROM duplicate_entries d WHERE s.entry_id = d.id;
+
+DELETE FROM entries WHERE id IN (SELECT id FROM duplicate_entries);
+
+SELECT rebuild_standings(contest_id) FROM (SELECT DISTINCT contest_id FROM duplicate_entries) affected;
+
+DROP TABLE duplicate_entries;
+
+ALTER TABLE entries ADD CONSTRAINT entries_contest_id_user_id_key UNIQUE (contest_id, user_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS rating_changes;
+
+ALTER TABLE contests DROP COLUMN IF EXISTS rated_at;
+ALTER TABLE contests DROP COLUMN IF EXISTS max_rating;
+ALTER TABLE contests DROP COLUMN IF EXISTS is_rated;
+
+ALTER TABLE users DROP COLUMN IF EXISTS rating;
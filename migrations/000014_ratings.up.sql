@@ -0,0 +1,18 @@
+ALTER TABLE users ADD COLUMN rating INTEGER DEFAULT 1500 NOT NULL;
+
+ALTER TABLE contests ADD COLUMN is_rated BOOLEAN DEFAULT false NOT NULL;
+ALTER TABLE contests ADD COLUMN max_rating INTEGER DEFAULT 0 NOT NULL; -- 0 - not limited
+ALTER TABLE contests ADD COLUMN rated_at TIMESTAMP; -- NULL - ratings are not calculated yet
+
+CREATE TABLE rating_changes
+(
+    contest_id INTEGER NOT NULL REFERENCES contests(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    rank INTEGER NOT NULL,
+    old_rating INTEGER NOT NULL,
+    new_rating INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
+
+CREATE INDEX rating_changes_user_id_idx ON rating_changes(user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS contest_templates;
+
+ALTER TABLE contests DROP COLUMN IF EXISTS languages;
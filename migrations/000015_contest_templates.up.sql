@@ -0,0 +1,14 @@
+ALTER TABLE contests ADD COLUMN languages VARCHAR(10)[] DEFAULT '{}' NOT NULL; -- empty - any language
+
+CREATE TABLE contest_templates
+(
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id),
+    name VARCHAR(64) NOT NULL,
+    duration_mins INTEGER NOT NULL,
+    scoring_mode scoring_mode DEFAULT 'points' NOT NULL,
+    allow_late_join BOOLEAN DEFAULT true NOT NULL,
+    languages VARCHAR(10)[] DEFAULT '{}' NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    UNIQUE (owner_id, name)
+);
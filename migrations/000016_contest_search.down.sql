@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS entries_team_id_idx;
+DROP INDEX IF EXISTS entries_user_id_idx;
+
+DROP INDEX IF EXISTS contests_creator_id_idx;
+DROP INDEX IF EXISTS contests_end_time_idx;
+DROP INDEX IF EXISTS contests_start_time_idx;
+
+DROP INDEX IF EXISTS contests_description_trgm_idx;
+DROP INDEX IF EXISTS contests_title_trgm_idx;
@@ -0,0 +1,12 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX contests_title_trgm_idx ON contests USING GIN (title gin_trgm_ops);
+CREATE INDEX contests_description_trgm_idx ON contests USING GIN (description gin_trgm_ops);
+
+CREATE INDEX contests_start_time_idx ON contests(start_time, id);
+CREATE INDEX contests_end_time_idx ON contests(end_time, id);
+CREATE INDEX contests_creator_id_idx ON contests(creator_id);
+
+-- NOTE: entries by contest are covered by the unique constraint on (contest_id, user_id)
+CREATE INDEX entries_user_id_idx ON entries(user_id);
+CREATE INDEX entries_team_id_idx ON entries(team_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS contest_roles;
+
+DROP TYPE IF EXISTS contest_role;
@@ -0,0 +1,10 @@
+CREATE TYPE contest_role AS ENUM ('co_author', 'tester', 'manager');
+
+CREATE TABLE contest_roles
+(
+    contest_id INTEGER NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    role contest_role NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (contest_id, user_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE contest_problems DROP CONSTRAINT IF EXISTS contest_problems_charcode_key;
+
+ALTER TABLE contest_problems DROP COLUMN IF EXISTS position;
@@ -0,0 +1,12 @@
+ALTER TABLE contest_problems ADD COLUMN position INTEGER DEFAULT 0 NOT NULL;
+
+-- NOTE: existing problems keep their alphabetical order
+UPDATE contest_problems cp
+SET position = o.position
+FROM (
+    SELECT contest_id, problem_id, ROW_NUMBER() OVER (PARTITION BY contest_id ORDER BY charcode) - 1 AS position
+    FROM contest_problems
+) o
+WHERE cp.contest_id = o.contest_id AND cp.problem_id = o.problem_id;
+
+ALTER TABLE contest_problems ADD CONSTRAINT contest_problems_charcode_key UNIQUE (contest_id, charcode);
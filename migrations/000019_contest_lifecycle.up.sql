@@ -0,0 +1,21 @@
+CREATE TABLE contest_lifecycle
+(
+    contest_id INTEGER NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL, -- event type, e.g. 'contest.started'
+    fired_at TIMESTAMP DEFAULT now() NOT NULL,
+    PRIMARY KEY (contest_id, type)
+);
+
+-- NOTE: lifecycle events of past contests are considered fired, except
+-- finalization of rated contests, which ratings are not calculated yet
+INSERT INTO contest_lifecycle (contest_id, type)
+SELECT id, 'contest.started' FROM contests WHERE start_time <= now();
+
+INSERT INTO contest_lifecycle (contest_id, type)
+SELECT id, 'contest.frozen' FROM contests WHERE freeze_mins > 0 AND end_time - make_interval(mins => freeze_mins) <= now();
+
+INSERT INTO contest_lifecycle (contest_id, type)
+SELECT id, 'contest.ended' FROM contests WHERE end_time <= now();
+
+INSERT INTO contest_lifecycle (contest_id, type)
+SELECT id, 'contest.finalized' FROM contests WHERE end_time <= now() AND NOT (is_rated AND rated_at IS NULL);
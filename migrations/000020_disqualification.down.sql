@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS contest_audit_log;
+
+ALTER TABLE entries DROP COLUMN IF EXISTS disqualification_reason;
+ALTER TABLE entries DROP COLUMN IF EXISTS disqualified_at;
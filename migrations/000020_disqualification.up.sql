@@ -0,0 +1,15 @@
+ALTER TABLE entries ADD COLUMN disqualified_at TIMESTAMP; -- NULL - not disqualified
+ALTER TABLE entries ADD COLUMN disqualification_reason TEXT DEFAULT '' NOT NULL;
+
+CREATE TABLE contest_audit_log
+(
+    id SERIAL PRIMARY KEY,
+    contest_id INTEGER NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    actor_id INTEGER NOT NULL REFERENCES users(id),
+    action VARCHAR(32) NOT NULL,
+    target_id INTEGER REFERENCES users(id), -- user affected by the action, if any
+    details TEXT DEFAULT '' NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX contest_audit_log_contest_id_idx ON contest_audit_log(contest_id);
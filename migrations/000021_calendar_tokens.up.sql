@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN calendar_token VARCHAR(64) UNIQUE; -- NULL - personal calendar feed is not issued
@@ -0,0 +1,3 @@
+ALTER TABLE contests DROP COLUMN IF EXISTS leaderboard_visibility;
+
+DROP TYPE IF EXISTS leaderboard_visibility;
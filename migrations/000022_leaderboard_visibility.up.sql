@@ -0,0 +1,3 @@
+CREATE TYPE leaderboard_visibility AS ENUM ('public', 'participants', 'after_end', 'hidden');
+
+ALTER TABLE contests ADD COLUMN leaderboard_visibility leaderboard_visibility DEFAULT 'public' NOT NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS problems_public_idx;
+DROP INDEX IF EXISTS problems_tags_idx;
+DROP INDEX IF EXISTS problems_search_vector_idx;
+
+ALTER TABLE problems DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE problems DROP COLUMN IF EXISTS published_at;
+ALTER TABLE problems DROP COLUMN IF EXISTS rating;
+ALTER TABLE problems DROP COLUMN IF EXISTS tags;
+ALTER TABLE problems DROP COLUMN IF EXISTS is_public;
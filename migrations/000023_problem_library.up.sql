@@ -0,0 +1,12 @@
+ALTER TABLE problems ADD COLUMN is_public BOOLEAN DEFAULT false NOT NULL;
+ALTER TABLE problems ADD COLUMN tags VARCHAR(32)[] DEFAULT '{}' NOT NULL;
+ALTER TABLE problems ADD COLUMN rating INTEGER DEFAULT 0 NOT NULL; -- numeric difficulty, 0 - not rated
+ALTER TABLE problems ADD COLUMN published_at TIMESTAMP;
+
+ALTER TABLE problems ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') || setweight(to_tsvector('english', statement), 'B')
+) STORED;
+
+CREATE INDEX problems_search_vector_idx ON problems USING GIN (search_vector);
+CREATE INDEX problems_tags_idx ON problems USING GIN (tags);
+CREATE INDEX problems_public_idx ON problems(published_at) WHERE is_public;
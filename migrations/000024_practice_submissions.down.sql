@@ -0,0 +1,21 @@
+DROP TRIGGER IF EXISTS submissions_refresh_standing ON submissions;
+CREATE TRIGGER submissions_refresh_standing
+    AFTER INSERT OR UPDATE OF verdict, passed_tests_count ON submissions
+    FOR EACH ROW
+    WHEN (NOT NEW.upsolving)
+    EXECUTE FUNCTION submissions_refresh_standing();
+
+DROP TRIGGER IF EXISTS submissions_leaderboard_updated ON submissions;
+CREATE TRIGGER submissions_leaderboard_updated
+    AFTER INSERT OR UPDATE OF verdict ON submissions
+    FOR EACH ROW
+    WHEN (NOT NEW.upsolving)
+    EXECUTE FUNCTION notify_leaderboard_updated();
+
+DELETE FROM failed_tests WHERE submission_id IN (SELECT id FROM submissions WHERE entry_id IS NULL);
+DELETE FROM submissions WHERE entry_id IS NULL;
+
+DROP INDEX IF EXISTS submissions_practice_idx;
+ALTER TABLE submissions DROP CONSTRAINT IF EXISTS submissions_owner_check;
+ALTER TABLE submissions DROP COLUMN IF EXISTS user_id;
+ALTER TABLE submissions ALTER COLUMN entry_id SET NOT NULL;
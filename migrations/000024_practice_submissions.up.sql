@@ -0,0 +1,22 @@
+-- NOTE: practice submissions are made on library problems outside of any contest,
+-- they have no entry and belong to the user directly
+ALTER TABLE submissions ALTER COLUMN entry_id DROP NOT NULL;
+ALTER TABLE submissions ADD COLUMN user_id INTEGER REFERENCES users(id);
+ALTER TABLE submissions ADD CONSTRAINT submissions_owner_check CHECK ((entry_id IS NULL) <> (user_id IS NULL));
+
+CREATE INDEX submissions_practice_idx ON submissions(user_id, problem_id) WHERE user_id IS NOT NULL;
+
+-- practice submissions never affect standings
+DROP TRIGGER submissions_leaderboard_updated ON submissions;
+CREATE TRIGGER submissions_leaderboard_updated
+    AFTER INSERT OR UPDATE OF verdict ON submissions
+    FOR EACH ROW
+    WHEN (NOT NEW.upsolving AND NEW.entry_id IS NOT NULL)
+    EXECUTE FUNCTION notify_leaderboard_updated();
+
+DROP TRIGGER submissions_refresh_standing ON submissions;
+CREATE TRIGGER submissions_refresh_standing
+    AFTER INSERT OR UPDATE OF verdict, passed_tests_count ON submissions
+    FOR EACH ROW
+    WHEN (NOT NEW.upsolving AND NEW.entry_id IS NOT NULL)
+    EXECUTE FUNCTION submissions_refresh_standing();
@@ -0,0 +1 @@
+ALTER TABLE contest_waitlist DROP COLUMN IF EXISTS invite_code;
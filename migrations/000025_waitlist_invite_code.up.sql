@@ -0,0 +1,2 @@
+-- NOTE: invite code used to join the waitlist of private contest, access is re-checked on admission
+ALTER TABLE contest_waitlist ADD COLUMN invite_code VARCHAR(64) DEFAULT '' NOT NULL;
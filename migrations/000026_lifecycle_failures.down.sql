@@ -0,0 +1 @@
+DROP TABLE IF EXISTS contest_lifecycle_failures;
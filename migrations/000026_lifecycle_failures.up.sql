@@ -0,0 +1,10 @@
+-- NOTE: failed lifecycle events are retried with exponential backoff, so they don't block other events
+CREATE TABLE contest_lifecycle_failures
+(
+    contest_id INTEGER NOT NULL REFERENCES contests(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    attempts INTEGER DEFAULT 0 NOT NULL,
+    last_error TEXT DEFAULT '' NOT NULL,
+    next_attempt_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (contest_id, type)
+);
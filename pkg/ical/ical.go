@@ -0,0 +1,89 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is a MIME type of written calendars
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is a limit of content line length in octets, longer lines are folded
+const maxLineLength = 75
+
+const timeFormat = "20060102T150405Z"
+
+type Event struct {
+	// UID should stay the same for the same event, so calendar clients update it instead of duplicating
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+}
+
+// Write writes events into a calendar (RFC 5545) named by name
+func Write(w io.Writer, name string, events []Event) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now()
+
+	line(bw, "BEGIN:VCALENDAR")
+	line(bw, "VERSION:2.0")
+	line(bw, "PRODID:-//voidcontests//contests//EN")
+	line(bw, "CALSCALE:GREGORIAN")
+	line(bw, "X-WR-CALNAME:"+escape(name))
+
+	for _, e := range events {
+		line(bw, "BEGIN:VEVENT")
+		line(bw, "UID:"+escape(e.UID))
+		// NOTE: stamp of generation forces clients to take the latest start and end times
+		line(bw, "DTSTAMP:"+stamp.UTC().Format(timeFormat))
+		line(bw, "DTSTART:"+e.Start.UTC().Format(timeFormat))
+		line(bw, "DTEND:"+e.End.UTC().Format(timeFormat))
+		line(bw, "SUMMARY:"+escape(e.Summary))
+		if e.Description != "" {
+			line(bw, "DESCRIPTION:"+escape(e.Description))
+		}
+		line(bw, "END:VEVENT")
+	}
+
+	line(bw, "END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// line writes content line terminated with CRLF, folding it by maxLineLength octets
+func line(w *bufio.Writer, s string) {
+	limit := maxLineLength
+	for len(s) > limit {
+		// NOTE: multi-octet characters are never split between lines
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+
+		// NOTE: continuation lines start with a space, which counts to the limit
+		limit = maxLineLength - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}